@@ -2,54 +2,48 @@ package healthchecks
 
 import (
 	"sync"
+	"time"
 )
 
 // Execute all statusEndpoint StatusCheck() functions asynchronously and return the
 // overall status by returning the highest severity item in the following order:
 // CRIT, WARN, OK
 func Aggregate(statusEndpoints []StatusEndpoint, typeFilter string, apiVersion APIVersion) string {
+	return SerializeStatusList(AggregateStatusList(statusEndpoints, typeFilter), apiVersion)
+}
 
-	if len(typeFilter) > 0 {
-		if typeFilter != "internal" && typeFilter != "external" {
-			sl := StatusList{
-				StatusList: []Status{
-					{
-						Description: "Invalid type",
-						Result:      CRITICAL,
-						Details:     "Unknown check type given for aggregate check",
-					},
-				},
-			}
-
-			return SerializeStatusList(sl, apiVersion)
-		}
+// AggregateStatusList is the same as Aggregate but returns the overall StatusList instead of
+// serializing it, for protocols other than the HTTP JSON API.
+func AggregateStatusList(statusEndpoints []StatusEndpoint, typeFilter string) StatusList {
+	s, ok := filterStatusEndpoints(statusEndpoints, typeFilter)
+	if !ok {
+		return invalidTypeStatusList()
 	}
 
-	s := statusEndpoints
-	if typeFilter != "" {
-		s = []StatusEndpoint{}
-		for _, statusEndpoint := range statusEndpoints {
-			if typeFilter == "internal" {
-				if statusEndpoint.Type == "internal" {
-					s = append(s, statusEndpoint)
-				}
-			} else if typeFilter == "external" {
-				if statusEndpoint.Type != "internal" {
-					s = append(s, statusEndpoint)
-				}
-			}
-		}
-	}
+	return aggregateStatusList(s, nil)
+}
+
+// The StatusList of an endpoint and the duration of its StatusCheck
+type endpointResult struct {
+	index      int
+	statusList StatusList
+	duration   time.Duration
+}
 
-	responses := make(chan StatusList)
+// Execute the StatusCheck of every endpoint asynchronously and return the overall StatusList, passing every result
+// to observe, if not nil, as it arrives
+func aggregateStatusList(s []StatusEndpoint, observe func(endpointResult)) StatusList {
+	responses := make(chan endpointResult)
 
 	var wg sync.WaitGroup
 	wg.Add(len(s))
 
-	for _, statusEndpoint := range s {
-		go func(statusEndpoint StatusEndpoint) {
-			responses <- statusEndpoint.StatusCheck.CheckStatus(statusEndpoint.Name)
-		}(statusEndpoint)
+	for i, statusEndpoint := range s {
+		go func(i int, statusEndpoint StatusEndpoint) {
+			start := time.Now()
+			statusList := statusEndpoint.StatusCheck.CheckStatus(statusEndpoint.Name)
+			responses <- endpointResult{index: i, statusList: statusList, duration: time.Since(start)}
+		}(i, statusEndpoint)
 	}
 
 	var crits []StatusList
@@ -57,7 +51,12 @@ func Aggregate(statusEndpoints []StatusEndpoint, typeFilter string, apiVersion A
 	var oks []StatusList
 
 	go func() {
-		for r := range responses {
+		for result := range responses {
+			if observe != nil {
+				observe(result)
+			}
+
+			r := result.statusList
 			switch r.StatusList[0].Result {
 			case CRITICAL:
 				crits = append(crits, r)
@@ -91,5 +90,45 @@ func Aggregate(statusEndpoints []StatusEndpoint, typeFilter string, apiVersion A
 		sl = warns[0]
 	}
 
-	return SerializeStatusList(sl, apiVersion)
+	return sl
+}
+
+// Filter the endpoints of a type, `internal` or `external`, or keep them all if the type is empty. Returns false if
+// the type is unknown.
+func filterStatusEndpoints(statusEndpoints []StatusEndpoint, typeFilter string) ([]StatusEndpoint, bool) {
+	if len(typeFilter) > 0 {
+		if typeFilter != "internal" && typeFilter != "external" {
+			return nil, false
+		}
+	}
+
+	s := statusEndpoints
+	if typeFilter != "" {
+		s = []StatusEndpoint{}
+		for _, statusEndpoint := range statusEndpoints {
+			if typeFilter == "internal" {
+				if statusEndpoint.Type == "internal" {
+					s = append(s, statusEndpoint)
+				}
+			} else if typeFilter == "external" {
+				if statusEndpoint.Type != "internal" {
+					s = append(s, statusEndpoint)
+				}
+			}
+		}
+	}
+
+	return s, true
+}
+
+func invalidTypeStatusList() StatusList {
+	return StatusList{
+		StatusList: []Status{
+			{
+				Description: "Invalid type",
+				Result:      CRITICAL,
+				Details:     "Unknown check type given for aggregate check",
+			},
+		},
+	}
 }
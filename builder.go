@@ -0,0 +1,123 @@
+package healthchecks
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Slugs of the built-in endpoints, which cannot be used by a StatusEndpoint
+var reservedSlugs = []string{"about", "aggregate", "am-i-up", "traverse", "v2"}
+
+// EndpointsBuilder builds a validated list of StatusEndpoints:
+//
+//	statusEndpoints, err := healthchecks.NewEndpointsBuilder().
+//		Internal("Redis", "redis", redisChecker).
+//		External("Billing", "billing", billingChecker).
+//		Traversable("Search", "search", "service", searchChecker, searchTraverser).
+//		Build()
+type EndpointsBuilder struct {
+	statusEndpoints []StatusEndpoint
+}
+
+// NewEndpointsBuilder creates an empty EndpointsBuilder
+func NewEndpointsBuilder() *EndpointsBuilder {
+	return &EndpointsBuilder{statusEndpoints: []StatusEndpoint{}}
+}
+
+// Add adds a StatusEndpoint as is
+func (b *EndpointsBuilder) Add(statusEndpoint StatusEndpoint) *EndpointsBuilder {
+	b.statusEndpoints = append(b.statusEndpoints, statusEndpoint)
+	return b
+}
+
+// Internal adds a non traversable StatusEndpoint of type `internal`
+func (b *EndpointsBuilder) Internal(name string, slug string, statusCheck StatusCheck) *EndpointsBuilder {
+	return b.Add(StatusEndpoint{
+		Name:          name,
+		Slug:          slug,
+		Type:          "internal",
+		IsTraversable: false,
+		StatusCheck:   statusCheck,
+		TraverseCheck: nil,
+	})
+}
+
+// External adds a non traversable StatusEndpoint of type `external`
+func (b *EndpointsBuilder) External(name string, slug string, statusCheck StatusCheck) *EndpointsBuilder {
+	return b.Add(StatusEndpoint{
+		Name:          name,
+		Slug:          slug,
+		Type:          "external",
+		IsTraversable: false,
+		StatusCheck:   statusCheck,
+		TraverseCheck: nil,
+	})
+}
+
+// Traversable adds a traversable StatusEndpoint, typically a service also implementing the healthchecks API
+func (b *EndpointsBuilder) Traversable(name string, slug string, endpointType string, statusCheck StatusCheck, traverseCheck TraverseCheck) *EndpointsBuilder {
+	return b.Add(StatusEndpoint{
+		Name:          name,
+		Slug:          slug,
+		Type:          endpointType,
+		IsTraversable: true,
+		StatusCheck:   statusCheck,
+		TraverseCheck: traverseCheck,
+	})
+}
+
+// Build validates the StatusEndpoints with ValidateStatusEndpoints and returns them
+func (b *EndpointsBuilder) Build() ([]StatusEndpoint, error) {
+	if err := ValidateStatusEndpoints(b.statusEndpoints); err != nil {
+		return nil, err
+	}
+
+	statusEndpoints := make([]StatusEndpoint, len(b.statusEndpoints))
+	copy(statusEndpoints, b.statusEndpoints)
+	return statusEndpoints, nil
+}
+
+// ValidateStatusEndpoints checks that every StatusEndpoint has a StatusCheck and a unique slug that is not empty,
+// does not contain a `/` and is not reserved by the built-in endpoints (about, aggregate, am-i-up, traverse and v2),
+// and that every traversable StatusEndpoint has a TraverseCheck. The returned error lists every invalid endpoint.
+func ValidateStatusEndpoints(statusEndpoints []StatusEndpoint) error {
+	errs := []error{}
+	seen := map[string]bool{}
+
+	for i, s := range statusEndpoints {
+		invalid := func(format string, a ...interface{}) {
+			errs = append(errs, fmt.Errorf("StatusEndpoint %d (%s): %s", i, s.Name, fmt.Sprintf(format, a...)))
+		}
+
+		switch {
+		case s.Slug == "":
+			invalid("slug is empty")
+		case strings.Contains(s.Slug, "/"):
+			invalid("slug `%s` contains a `/`", s.Slug)
+		case isReservedSlug(s.Slug):
+			invalid("slug `%s` is reserved", s.Slug)
+		case seen[s.Slug]:
+			invalid("slug `%s` is duplicated", s.Slug)
+		}
+		seen[s.Slug] = true
+
+		if s.StatusCheck == nil {
+			invalid("StatusCheck is nil")
+		}
+		if s.IsTraversable && s.TraverseCheck == nil {
+			invalid("traversable but TraverseCheck is nil")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func isReservedSlug(slug string) bool {
+	for _, reserved := range reservedSlugs {
+		if strings.EqualFold(slug, reserved) {
+			return true
+		}
+	}
+	return false
+}
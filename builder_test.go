@@ -0,0 +1,87 @@
+package healthchecks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEndpointsBuilder(t *testing.T) {
+	ok := StatusCheckFunc(OKStatus)
+	traverse := MockStatusChecker{"UUU", OK, "all good"}
+
+	statusEndpoints, err := NewEndpointsBuilder().
+		Internal("Redis", "redis", ok).
+		External("Billing", "billing", ok).
+		Traversable("Search", "search", "service", ok, traverse).
+		Add(StatusEndpoint{Name: "Custom", Slug: "custom", Type: "internal", StatusCheck: ok}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build should succeed, was `%s`", err.Error())
+	}
+
+	expected := []struct {
+		slug          string
+		endpointType  string
+		isTraversable bool
+	}{
+		{"redis", "internal", false},
+		{"billing", "external", false},
+		{"search", "service", true},
+		{"custom", "internal", false},
+	}
+	if len(statusEndpoints) != len(expected) {
+		t.Fatalf("Length of StatusEndpoints should be %d, was %d", len(expected), len(statusEndpoints))
+	}
+	for i, e := range expected {
+		actual := statusEndpoints[i]
+		if actual.Slug != e.slug || actual.Type != e.endpointType || actual.IsTraversable != e.isTraversable {
+			t.Errorf("StatusEndpoint should be `%s` `%s` traversable=%t, was `%v`", e.slug, e.endpointType, e.isTraversable, actual)
+		}
+	}
+	if statusEndpoints[2].TraverseCheck == nil {
+		t.Errorf("TraverseCheck of `search` should be set")
+	}
+}
+
+func TestEndpointsBuilderInvalid(t *testing.T) {
+	ok := StatusCheckFunc(OKStatus)
+
+	_, err := NewEndpointsBuilder().
+		Internal("Empty", "", ok).
+		Internal("About", "about", ok).
+		Internal("Version 2", "V2", ok).
+		Internal("Nested", "a/b", ok).
+		Internal("Redis", "redis", ok).
+		External("Redis again", "redis", ok).
+		Internal("No check", "nocheck", nil).
+		Add(StatusEndpoint{Name: "Search", Slug: "search", Type: "service", IsTraversable: true, StatusCheck: ok}).
+		Build()
+	if err == nil {
+		t.Fatalf("Build should fail")
+	}
+
+	expected := []string{
+		"StatusEndpoint 0 (Empty): slug is empty",
+		"StatusEndpoint 1 (About): slug `about` is reserved",
+		"StatusEndpoint 2 (Version 2): slug `V2` is reserved",
+		"StatusEndpoint 3 (Nested): slug `a/b` contains a `/`",
+		"StatusEndpoint 5 (Redis again): slug `redis` is duplicated",
+		"StatusEndpoint 6 (No check): StatusCheck is nil",
+		"StatusEndpoint 7 (Search): traversable but TraverseCheck is nil",
+	}
+	if err.Error() != strings.Join(expected, "\n") {
+		t.Errorf("Error should be `%s`, was `%s`", strings.Join(expected, "\n"), err.Error())
+	}
+}
+
+func TestValidateStatusEndpoints(t *testing.T) {
+	err := ValidateStatusEndpoints([]StatusEndpoint{testStatusEndpointNotTraversable, testStatusEndpointTraversable})
+	if err != nil {
+		t.Errorf("StatusEndpoints should be valid, was `%s`", err.Error())
+	}
+
+	err = ValidateStatusEndpoints([]StatusEndpoint{testStatusEndpointMissingTraverseChecker})
+	if err == nil || err.Error() != "StatusEndpoint 0 (TTT): traversable but TraverseCheck is nil" {
+		t.Errorf("Error should be the missing TraverseCheck, was `%v`", err)
+	}
+}
@@ -0,0 +1,80 @@
+package breakersc
+
+import (
+	"fmt"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// State of a circuit breaker
+type State int
+
+const (
+	StateClosed State = iota
+	StateHalfOpen
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "CLOSED"
+	case StateHalfOpen:
+		return "HALF-OPEN"
+	case StateOpen:
+		return "OPEN"
+	default:
+		return fmt.Sprintf("UNKNOWN(%d)", int(s))
+	}
+}
+
+// Counts of the requests handled by a circuit breaker, in its current state or window
+type Counts struct {
+	Requests             uint32
+	TotalSuccesses       uint32
+	TotalFailures        uint32
+	ConsecutiveSuccesses uint32
+	ConsecutiveFailures  uint32
+}
+
+// A circuit breaker whose state is checked. GoBreakerAdapter and ResiliencyAdapter adapt the sony/gobreaker and
+// eapache/go-resiliency circuit breakers.
+type CircuitBreaker interface {
+	State() State
+	Counts() Counts
+}
+
+// CircuitBreakerStatusChecker checks the state of a circuit breaker: CLOSED is OK, HALF-OPEN is WARN and OPEN is
+// CRITICAL.
+type CircuitBreakerStatusChecker struct {
+	Breaker CircuitBreaker
+}
+
+func (c CircuitBreakerStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	state := c.Breaker.State()
+	counts := c.Breaker.Counts()
+
+	s := healthchecks.Status{
+		Description: name,
+		Result:      healthchecks.OK,
+		Details: fmt.Sprintf(
+			"Circuit breaker is %s; requests=%d successes=%d failures=%d consecutive_failures=%d",
+			state,
+			counts.Requests,
+			counts.TotalSuccesses,
+			counts.TotalFailures,
+			counts.ConsecutiveFailures,
+		),
+	}
+
+	switch state {
+	case StateClosed:
+		s.Result = healthchecks.OK
+	case StateHalfOpen:
+		s.Result = healthchecks.WARNING
+	default:
+		s.Result = healthchecks.CRITICAL
+	}
+
+	return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+}
@@ -0,0 +1,69 @@
+package breakersc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eapache/go-resiliency/breaker"
+	"github.com/hootsuite/healthchecks"
+	"github.com/sony/gobreaker"
+)
+
+func TestGoBreaker(t *testing.T) {
+	cb := gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:    "test",
+		Timeout: 50 * time.Millisecond,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 2
+		},
+	})
+	statusChecker := CircuitBreakerStatusChecker{Breaker: GoBreakerAdapter{Breaker: cb}}
+
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("An error") })
+
+	assertStatus(t, statusChecker, healthchecks.OK, "Circuit breaker is CLOSED; requests=2 successes=1 failures=1 consecutive_failures=1")
+
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("An error") })
+
+	assertStatus(t, statusChecker, healthchecks.CRITICAL, "Circuit breaker is OPEN; requests=0 successes=0 failures=0 consecutive_failures=0")
+
+	time.Sleep(60 * time.Millisecond)
+
+	assertStatus(t, statusChecker, healthchecks.WARNING, "Circuit breaker is HALF-OPEN; requests=0 successes=0 failures=0 consecutive_failures=0")
+}
+
+func TestResiliencyBreaker(t *testing.T) {
+	b := breaker.New(1, 1, 50*time.Millisecond)
+	statusChecker := CircuitBreakerStatusChecker{Breaker: ResiliencyAdapter{Breaker: b}}
+
+	assertStatus(t, statusChecker, healthchecks.OK, "Circuit breaker is CLOSED; requests=0 successes=0 failures=0 consecutive_failures=0")
+
+	b.Run(func() error { return errors.New("An error") })
+
+	assertStatus(t, statusChecker, healthchecks.CRITICAL, "Circuit breaker is OPEN; requests=0 successes=0 failures=0 consecutive_failures=0")
+
+	time.Sleep(60 * time.Millisecond)
+
+	assertStatus(t, statusChecker, healthchecks.WARNING, "Circuit breaker is HALF-OPEN; requests=0 successes=0 failures=0 consecutive_failures=0")
+}
+
+func assertStatus(t *testing.T, statusChecker CircuitBreakerStatusChecker, eResult healthchecks.AlertLevel, eDetails string) {
+	t.Helper()
+
+	s := statusChecker.CheckStatus("The breaker")
+
+	if len(s.StatusList) != 1 {
+		t.Fatalf("Length of StatusList should be 1, was %d", len(s.StatusList))
+	}
+
+	actual := s.StatusList[0]
+	if actual.Result != eResult {
+		t.Errorf("Result should be `%s`, was `%s`", eResult, actual.Result)
+	}
+
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
@@ -0,0 +1,38 @@
+package breakersc
+
+import (
+	"github.com/sony/gobreaker"
+)
+
+// A sony/gobreaker circuit breaker, either a *gobreaker.CircuitBreaker or a *gobreaker.TwoStepCircuitBreaker
+type GoBreaker interface {
+	State() gobreaker.State
+	Counts() gobreaker.Counts
+}
+
+// GoBreakerAdapter adapts a sony/gobreaker circuit breaker to a CircuitBreaker
+type GoBreakerAdapter struct {
+	Breaker GoBreaker
+}
+
+func (g GoBreakerAdapter) State() State {
+	switch g.Breaker.State() {
+	case gobreaker.StateClosed:
+		return StateClosed
+	case gobreaker.StateHalfOpen:
+		return StateHalfOpen
+	default:
+		return StateOpen
+	}
+}
+
+func (g GoBreakerAdapter) Counts() Counts {
+	counts := g.Breaker.Counts()
+	return Counts{
+		Requests:             counts.Requests,
+		TotalSuccesses:       counts.TotalSuccesses,
+		TotalFailures:        counts.TotalFailures,
+		ConsecutiveSuccesses: counts.ConsecutiveSuccesses,
+		ConsecutiveFailures:  counts.ConsecutiveFailures,
+	}
+}
@@ -0,0 +1,26 @@
+package breakersc
+
+import (
+	"github.com/eapache/go-resiliency/breaker"
+)
+
+// ResiliencyAdapter adapts an eapache/go-resiliency circuit breaker to a CircuitBreaker. The go-resiliency breaker
+// doesn't expose its counts, they are always zero.
+type ResiliencyAdapter struct {
+	Breaker *breaker.Breaker
+}
+
+func (r ResiliencyAdapter) State() State {
+	switch r.Breaker.GetState() {
+	case breaker.Closed:
+		return StateClosed
+	case breaker.HalfOpen:
+		return StateHalfOpen
+	default:
+		return StateOpen
+	}
+}
+
+func (r ResiliencyAdapter) Counts() Counts {
+	return Counts{}
+}
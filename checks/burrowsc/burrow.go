@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/hootsuite/healthchecks"
@@ -18,8 +19,25 @@ const (
 	statusStop     = "STOP"
 	statusStall    = "STALL"
 	statusNotFound = "NOTFOUND"
+	statusRewind   = "REWIND" // Partition status only returned by the v3 API
 )
 
+// Burrow API versions supported by the BurrowStatusChecker
+type APIVersion int
+
+const (
+	APIV2 APIVersion = iota // Burrow v2 API, the default
+	APIV3                   // Burrow v3 API
+)
+
+// Default lag endpoint paths, relative to the BaseUrl. `{cluster}` and `{group}` are replaced by the Kafka cluster and
+// consumer group.
+const (
+	DefaultV2LagPath = "/{cluster}/consumer/{group}/lag"
+	DefaultV3LagPath = "/v3/kafka/{cluster}/consumer/{group}/lag"
+)
+
+// Response of the Burrow v2 and v3 lag endpoints (https://github.com/linkedin/Burrow/wiki/http-request-consumer-group-status)
 type lagResponse struct {
 	Error     bool      `json:"error"`
 	Message   string    `json:"message"`
@@ -37,42 +55,57 @@ type lagStatus struct {
 	Complete       float64     `json:"complete"`
 	Partitions     []partition `json:"partitions"`
 	PartitionCount int         `json:"partition_count"`
-	MaxLag         partition   `json:"maxlag"`
+	MaxLag         partition   `json:"maxlag"` // Left empty when null, for a v3 group without partitions
 	TotalLag       int64       `json:"totallag"`
 }
 
 type partition struct {
-	Topic     string `json:"topic"`
-	Partition int    `json:"partition"`
-	Owner     string `json:"owner"`
-	ClientID  string `json:"client_id"`
-	Status    string `json:"status"`
-	Start     struct {
-		Offset    int64 `json:"offset"`
-		Timestamp int64 `json:"timestamp"`
-		Lag       int64 `json:"lag"`
-	} `json:"start"`
-	End struct {
-		Offset    int64 `json:"offset"`
-		Timestamp int64 `json:"timestamp"`
-		Lag       int64 `json:"lag"`
-	} `json:"end"`
-	CurrentLag int64   `json:"current_lag"`
-	Complete   float64 `json:"complete"`
+	Topic      string          `json:"topic"`
+	Partition  int             `json:"partition"`
+	Owner      string          `json:"owner"`
+	ClientID   string          `json:"client_id"`
+	Status     string          `json:"status"`
+	Start      partitionOffset `json:"start"`
+	End        partitionOffset `json:"end"`
+	CurrentLag int64           `json:"current_lag"`
+	Complete   float64         `json:"complete"`
+}
+
+type partitionOffset struct {
+	Offset     int64 `json:"offset"`
+	Timestamp  int64 `json:"timestamp"`
+	ObservedAt int64 `json:"observedAt"` // Only returned by the v3 API
+	Lag        int64 `json:"lag"`
 }
 
 type BurrowStatusChecker struct {
-	BaseUrl              string  // Base url of the Burrow API
-	Cluster              string  // The Kafka cluster to monitor
-	ConsumerGroup        string  // The consumer group on the cluster to monitor
-	Topic                *string // Optional if monitoring the status of a specific topic, leave nil to get the consumer group status
-	CriticalLagThreshold *int64  // Optional lag threshold to trigger a critical alert when exceeded
+	BaseUrl              string           // Base url of the Burrow API
+	APIVersion           APIVersion       // Optional Burrow API version, defaults to APIV2
+	LagPath              string           // Optional path of the lag endpoint, defaults to DefaultV2LagPath or DefaultV3LagPath
+	Cluster              string           // The Kafka cluster to monitor
+	ConsumerGroup        string           // The consumer group on the cluster to monitor
+	Topic                *string          // Optional if monitoring the status of a specific topic, leave nil to get the consumer group status
+	WarningLagThreshold  *int64           // Optional lag threshold to trigger a warning alert when exceeded
+	CriticalLagThreshold *int64           // Optional lag threshold to trigger a critical alert when exceeded
+	Trend                *TrendThresholds // Optional thresholds of the lag trend of the group, or of the topic if set
+}
+
+// LagThresholds are the total lag thresholds of a consumer group or topic, a nil threshold is disabled.
+type LagThresholds struct {
+	Warning  *int64 // Optional lag threshold to trigger a warning alert when exceeded
+	Critical *int64 // Optional lag threshold to trigger a critical alert when exceeded
+}
+
+func (t LagThresholds) exceedsCritical(lag int64) bool {
+	return t.Critical != nil && lag > *t.Critical
+}
+
+func (t LagThresholds) exceedsWarning(lag int64) bool {
+	return t.Warning != nil && lag > *t.Warning
 }
 
 func (b BurrowStatusChecker) CheckStatus(name string) healthchecks.StatusList {
-	baseUrl := strings.TrimSuffix(b.BaseUrl, "/")
-	url := fmt.Sprintf("%s/%s/consumer/%s/lag", baseUrl, b.Cluster, b.ConsumerGroup)
-	req, err := http.NewRequest("GET", url, nil)
+	lagStatus, err := fetchLagStatus(b.BaseUrl, b.LagPath, b.APIVersion, b.Cluster, b.ConsumerGroup)
 	if err != nil {
 		return healthchecks.StatusList{
 			StatusList: []healthchecks.Status{
@@ -85,68 +118,83 @@ func (b BurrowStatusChecker) CheckStatus(name string) healthchecks.StatusList {
 		}
 	}
 
+	thresholds := LagThresholds{Warning: b.WarningLagThreshold, Critical: b.CriticalLagThreshold}
+
+	var s healthchecks.Status
+	if b.Topic == nil {
+		s = checkGroupStatus(name, lagStatus, thresholds)
+	} else {
+		s = checkTopicStatus(name, *b.Topic, lagStatus, thresholds)
+	}
+
+	if b.Trend != nil {
+		s = checkTrend(s, topicPartitions(lagStatus.Partitions, b.Topic), *b.Trend)
+	}
+
+	return healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			s,
+		},
+	}
+}
+
+// Build the url of the lag endpoint of a consumer group
+func lagURL(baseUrl string, lagPath string, apiVersion APIVersion, cluster string, group string) string {
+	if lagPath == "" {
+		lagPath = DefaultV2LagPath
+		if apiVersion == APIV3 {
+			lagPath = DefaultV3LagPath
+		}
+	}
+
+	path := strings.NewReplacer(
+		"{cluster}", url.PathEscape(cluster),
+		"{group}", url.PathEscape(group),
+	).Replace(lagPath)
+
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(baseUrl, "/"), strings.TrimPrefix(path, "/"))
+}
+
+// Get the lag status of a consumer group from Burrow, decoding the response of the given API version
+func fetchLagStatus(baseUrl string, lagPath string, apiVersion APIVersion, cluster string, group string) (lagStatus, error) {
+	req, err := http.NewRequest("GET", lagURL(baseUrl, lagPath, apiVersion, cluster, group), nil)
+	if err != nil {
+		return lagStatus{}, err
+	}
+
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return healthchecks.StatusList{
-			StatusList: []healthchecks.Status{
-				{
-					Description: name,
-					Result:      healthchecks.CRITICAL,
-					Details:     err.Error(),
-				},
-			},
-		}
+		return lagStatus{}, err
 	}
 
 	// Callers should close resp.Body when done reading from it
 	// Defer the closing of the body
 	defer resp.Body.Close()
 
+	// Like v2, the v3 API responds with a 404 when the cluster or consumer group is unknown
 	if resp.StatusCode != http.StatusOK {
 		responseBody, _ := ioutil.ReadAll(resp.Body)
-		return healthchecks.StatusList{
-			StatusList: []healthchecks.Status{
-				{
-					Description: name,
-					Result:      healthchecks.CRITICAL,
-					Details:     fmt.Sprintf("Invalid response. Code: %d, Body: %s", resp.StatusCode, responseBody),
-				},
-			},
-		}
+		return lagStatus{}, fmt.Errorf("Invalid response. Code: %d, Body: %s", resp.StatusCode, responseBody)
 	}
 
 	lagResponse := lagResponse{}
 	err = json.NewDecoder(resp.Body).Decode(&lagResponse)
 	if err != nil {
-		return healthchecks.StatusList{
-			StatusList: []healthchecks.Status{
-				{
-					Description: name,
-					Result:      healthchecks.CRITICAL,
-					Details:     fmt.Sprintf("Error decoding json response: %s", err.Error()),
-				},
-			},
-		}
+		return lagStatus{}, fmt.Errorf("Error decoding json response: %s", err.Error())
 	}
 
-	var s healthchecks.Status
-	if b.Topic == nil {
-		s = checkGroupStatus(name, lagResponse.LagStatus, b.CriticalLagThreshold)
-	} else {
-		s = checkTopicStatus(name, *b.Topic, lagResponse.LagStatus, b.CriticalLagThreshold)
+	// The v3 API reports some failures, such as a timeout of Burrow, in the body of a 200 response
+	if apiVersion == APIV3 && lagResponse.Error {
+		return lagStatus{}, fmt.Errorf("Burrow returned an error: %s", lagResponse.Message)
 	}
 
-	return healthchecks.StatusList{
-		StatusList: []healthchecks.Status{
-			s,
-		},
-	}
+	return lagResponse.LagStatus, nil
 }
 
-func checkGroupStatus(name string, lagStatus lagStatus, criticalLagThreshold *int64) healthchecks.Status {
+func checkGroupStatus(name string, lagStatus lagStatus, thresholds LagThresholds) healthchecks.Status {
 	// If critical lag threshold is specified and exceeded, return a critical alert
-	if criticalLagThreshold != nil && lagStatus.TotalLag > *criticalLagThreshold {
+	if thresholds.exceedsCritical(lagStatus.TotalLag) {
 		return healthchecks.Status{
 			Description: name,
 			Result:      healthchecks.CRITICAL,
@@ -154,15 +202,25 @@ func checkGroupStatus(name string, lagStatus lagStatus, criticalLagThreshold *in
 		}
 	}
 
-	// If critical lag threshold was not exceeded or not specified, check Burrow consumer group status
+	// If warning lag threshold is specified and exceeded, raise a warning unless Burrow reports the group as critical
+	alertLevel := getAlertLevel(lagStatus.Status)
+	if alertLevel != healthchecks.CRITICAL && thresholds.exceedsWarning(lagStatus.TotalLag) {
+		return healthchecks.Status{
+			Description: name,
+			Result:      healthchecks.WARNING,
+			Details:     fmt.Sprintf("%s exceeds warning threshold", formatConsumerGroupDetails(lagStatus)),
+		}
+	}
+
+	// If no lag threshold was exceeded or specified, check Burrow consumer group status
 	return healthchecks.Status{
 		Description: name,
-		Result:      getAlertLevel(lagStatus.Status),
+		Result:      alertLevel,
 		Details:     formatConsumerGroupDetails(lagStatus),
 	}
 }
 
-func checkTopicStatus(name string, topic string, lagStatus lagStatus, criticalLagThreshold *int64) healthchecks.Status {
+func checkTopicStatus(name string, topic string, lagStatus lagStatus, thresholds LagThresholds) healthchecks.Status {
 	alertLevel := healthchecks.OK
 	critWarnPartitions := []partition{}
 	topicExists := false
@@ -203,7 +261,7 @@ func checkTopicStatus(name string, topic string, lagStatus lagStatus, criticalLa
 	partitionDetails := formatPartitionsDetails(critWarnPartitions)
 
 	// If critical lag threshold is specified and exceeded, return a critical alert
-	if criticalLagThreshold != nil && totalLag > *criticalLagThreshold {
+	if thresholds.exceedsCritical(totalLag) {
 		return healthchecks.Status{
 			Description: name,
 			Result:      healthchecks.CRITICAL,
@@ -211,7 +269,16 @@ func checkTopicStatus(name string, topic string, lagStatus lagStatus, criticalLa
 		}
 	}
 
-	// If critical lag threshold was not exceeded or not specified, check Burrow partition statuses
+	// If warning lag threshold is specified and exceeded, raise a warning unless a partition is critical
+	if alertLevel != healthchecks.CRITICAL && thresholds.exceedsWarning(totalLag) {
+		return healthchecks.Status{
+			Description: name,
+			Result:      healthchecks.WARNING,
+			Details:     fmt.Sprintf("%s exceeds warning threshold%s", topicDetails, partitionDetails),
+		}
+	}
+
+	// If no lag threshold was exceeded or specified, check Burrow partition statuses
 	return healthchecks.Status{
 		Description: name,
 		Result:      alertLevel,
@@ -227,7 +294,7 @@ func getAlertLevel(status string) healthchecks.AlertLevel {
 		alertLevel = healthchecks.OK
 	case statusWarn, statusNotFound:
 		alertLevel = healthchecks.WARNING
-	case statusErr, statusStop, statusStall:
+	case statusErr, statusStop, statusStall, statusRewind:
 		alertLevel = healthchecks.CRITICAL
 	default:
 		alertLevel = healthchecks.WARNING
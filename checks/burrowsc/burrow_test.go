@@ -135,6 +135,41 @@ func TestBurrowStatusChecker_CheckTopicStatusThresholdExceeded(t *testing.T) {
 	}
 }
 
+func TestBurrowStatusChecker_CheckTopicStatusWarningThresholdExceeded(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/kafka/cluster1/consumer/group1/lag",
+		httpmock.NewStringResponder(200, getTestData("status_ok.json")))
+
+	topic := "topic2"
+	warningLagThreshold := int64(10)
+	criticalLagThreshold := int64(100)
+	burrowStatusChecker := BurrowStatusChecker{
+		BaseUrl:              "http://something.com/kafka",
+		Cluster:              "cluster1",
+		ConsumerGroup:        "group1",
+		Topic:                &topic,
+		WarningLagThreshold:  &warningLagThreshold,
+		CriticalLagThreshold: &criticalLagThreshold,
+	}
+	status := burrowStatusChecker.CheckStatus("Consumer")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Consumer",
+				Result:      healthchecks.WARNING,
+				Details:     "Topic topic2 has total lag of 11 for group group1 on cluster cluster1 exceeds warning threshold",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
 func TestBurrowStatusChecker_CheckGroupStatusERR(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
@@ -464,6 +499,161 @@ func TestBurrowStatusChecker_CheckStatusTrimBaseUrl(t *testing.T) {
 	}
 }
 
+func TestBurrowStatusChecker_CheckGroupStatusV3(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/v3/kafka/cluster1/consumer/group1/lag",
+		httpmock.NewStringResponder(200, getTestData("v3_status_rewind.json")))
+
+	burrowStatusChecker := BurrowStatusChecker{
+		BaseUrl:       "http://something.com/",
+		APIVersion:    APIV3,
+		Cluster:       "cluster1",
+		ConsumerGroup: "group1",
+	}
+	status := burrowStatusChecker.CheckStatus("Consumer")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Consumer",
+				Result:      healthchecks.CRITICAL,
+				Details:     "Consumer group status is ERR, total lag of 11 for group group1 on cluster cluster1",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
+func TestBurrowStatusChecker_CheckTopicStatusV3REWIND(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/v3/kafka/cluster1/consumer/group1/lag",
+		httpmock.NewStringResponder(200, getTestData("v3_status_rewind.json")))
+
+	topic := "topic2"
+	burrowStatusChecker := BurrowStatusChecker{
+		BaseUrl:       "http://something.com",
+		APIVersion:    APIV3,
+		Cluster:       "cluster1",
+		ConsumerGroup: "group1",
+		Topic:         &topic,
+	}
+	status := burrowStatusChecker.CheckStatus("Consumer")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Consumer",
+				Result:      healthchecks.CRITICAL,
+				Details:     "Topic topic2 has total lag of 9 for group group1 on cluster cluster1, partition 0 status is REWIND and has lag of 9",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
+func TestBurrowStatusChecker_CheckGroupNotFoundV3(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/v3/kafka/cluster1/consumer/group1/lag",
+		httpmock.NewStringResponder(404, `{"error":true,"message":"cluster or consumer not found","request":{"url":"/v3/kafka/cluster1/consumer/group1/lag","host":"burrow1"}}`))
+
+	burrowStatusChecker := BurrowStatusChecker{
+		BaseUrl:       "http://something.com",
+		APIVersion:    APIV3,
+		Cluster:       "cluster1",
+		ConsumerGroup: "group1",
+	}
+	status := burrowStatusChecker.CheckStatus("Consumer")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Consumer",
+				Result:      healthchecks.CRITICAL,
+				Details:     `Invalid response. Code: 404, Body: {"error":true,"message":"cluster or consumer not found","request":{"url":"/v3/kafka/cluster1/consumer/group1/lag","host":"burrow1"}}`,
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
+func TestBurrowStatusChecker_CheckStatusErrorV3(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/v3/kafka/cluster1/consumer/group1/lag",
+		httpmock.NewStringResponder(200, `{"error":true,"message":"timeout fetching consumer status"}`))
+
+	burrowStatusChecker := BurrowStatusChecker{
+		BaseUrl:       "http://something.com",
+		APIVersion:    APIV3,
+		Cluster:       "cluster1",
+		ConsumerGroup: "group1",
+	}
+	status := burrowStatusChecker.CheckStatus("Consumer")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Consumer",
+				Result:      healthchecks.CRITICAL,
+				Details:     "Burrow returned an error: timeout fetching consumer status",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
+func TestBurrowStatusChecker_CheckStatusCustomLagPath(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/burrow/v3/kafka/cluster1/consumer/group1/lag",
+		httpmock.NewStringResponder(200, getTestData("v3_status_rewind.json")))
+
+	topic := "topic1"
+	burrowStatusChecker := BurrowStatusChecker{
+		BaseUrl:       "http://something.com",
+		APIVersion:    APIV3,
+		LagPath:       "/burrow/v3/kafka/{cluster}/consumer/{group}/lag",
+		Cluster:       "cluster1",
+		ConsumerGroup: "group1",
+		Topic:         &topic,
+	}
+	status := burrowStatusChecker.CheckStatus("Consumer")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Consumer",
+				Result:      healthchecks.OK,
+				Details:     "Topic topic1 has total lag of 2 for group group1 on cluster cluster1",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
 func getTestData(filename string) string {
 	file, e := ioutil.ReadFile(fmt.Sprintf("./test/%s", filename))
 	if e != nil {
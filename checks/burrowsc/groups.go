@@ -0,0 +1,207 @@
+package burrowsc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// Default consumer listing paths, relative to the BaseUrl. `{cluster}` is replaced by the Kafka cluster.
+const (
+	DefaultV2ConsumersPath = "/{cluster}/consumer"
+	DefaultV3ConsumersPath = "/v3/kafka/{cluster}/consumer"
+)
+
+type consumersResponse struct {
+	Error     bool     `json:"error"`
+	Message   string   `json:"message"`
+	Consumers []string `json:"consumers"`
+}
+
+// BurrowGroupsStatusChecker checks the lag of several consumer groups of a Kafka cluster.
+//
+// The groups to check are either listed in ConsumerGroups or, if empty, every consumer group Burrow knows on the
+// cluster. Each group is checked against its entry in GroupThresholds, or Thresholds if it has none, and each topic of
+// TopicThresholds the group consumes is checked against its own thresholds. The returned StatusList starts with the
+// worst status of the groups, followed by one entry per group sorted worst-first. A cluster without consumer groups is
+// OK.
+type BurrowGroupsStatusChecker struct {
+	BaseUrl         string                   // Base url of the Burrow API
+	APIVersion      APIVersion               // Optional Burrow API version, defaults to APIV2
+	LagPath         string                   // Optional path of the lag endpoint, defaults to DefaultV2LagPath or DefaultV3LagPath
+	ConsumersPath   string                   // Optional path of the consumer listing, defaults to DefaultV2ConsumersPath or DefaultV3ConsumersPath
+	Cluster         string                   // The Kafka cluster to monitor
+	ConsumerGroups  []string                 // Optional consumer groups to monitor, leave empty to monitor every group of the cluster
+	Thresholds      LagThresholds            // Optional lag thresholds of every group
+	GroupThresholds map[string]LagThresholds // Optional lag thresholds of specific groups, overriding Thresholds
+	TopicThresholds map[string]LagThresholds // Optional lag thresholds of specific topics, checked in every group consuming them
+	Trend           *TrendThresholds         // Optional thresholds of the lag trend of every group
+}
+
+func (b BurrowGroupsStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	groups := b.ConsumerGroups
+	if len(groups) == 0 {
+		var err error
+		groups, err = fetchConsumerGroups(b.BaseUrl, b.ConsumersPath, b.APIVersion, b.Cluster)
+		if err != nil {
+			return healthchecks.StatusList{
+				StatusList: []healthchecks.Status{
+					{
+						Description: name,
+						Result:      healthchecks.CRITICAL,
+						Details:     err.Error(),
+					},
+				},
+			}
+		}
+	}
+
+	if len(groups) == 0 {
+		return healthchecks.StatusList{
+			StatusList: []healthchecks.Status{
+				{
+					Description: name,
+					Result:      healthchecks.OK,
+					Details:     fmt.Sprintf("No consumer groups to check on cluster %s", b.Cluster),
+				},
+			},
+		}
+	}
+
+	statuses := make([]healthchecks.Status, len(groups))
+	var wg sync.WaitGroup
+	for i, group := range groups {
+		wg.Add(1)
+		go func(i int, group string) {
+			defer wg.Done()
+			statuses[i] = b.checkGroup(group)
+		}(i, group)
+	}
+	wg.Wait()
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		if healthchecks.Severity(statuses[i].Result) != healthchecks.Severity(statuses[j].Result) {
+			return healthchecks.Severity(statuses[i].Result) > healthchecks.Severity(statuses[j].Result)
+		}
+		return statuses[i].Description < statuses[j].Description
+	})
+
+	s := healthchecks.Status{
+		Description: name,
+		Result:      statuses[0].Result,
+		Details:     "",
+	}
+	lagging := []string{}
+	for _, status := range statuses {
+		if status.Result != healthchecks.OK {
+			lagging = append(lagging, status.Description)
+		}
+	}
+	s.Details = fmt.Sprintf("%d of %d consumer groups lagging on cluster %s", len(lagging), len(statuses), b.Cluster)
+	if len(lagging) > 0 {
+		s.Details = fmt.Sprintf("%s: %s", s.Details, strings.Join(lagging, ", "))
+	}
+
+	return healthchecks.StatusList{StatusList: append([]healthchecks.Status{s}, statuses...)}
+}
+
+func (b BurrowGroupsStatusChecker) checkGroup(group string) healthchecks.Status {
+	lagStatus, err := fetchLagStatus(b.BaseUrl, b.LagPath, b.APIVersion, b.Cluster, group)
+	if err != nil {
+		return healthchecks.Status{
+			Description: group,
+			Result:      healthchecks.CRITICAL,
+			Details:     err.Error(),
+		}
+	}
+
+	thresholds, ok := b.GroupThresholds[group]
+	if !ok {
+		thresholds = b.Thresholds
+	}
+	s := checkGroupStatus(group, lagStatus, thresholds)
+
+	// Check the topics with thresholds the group consumes, in a stable order
+	topics := []string{}
+	for _, partition := range lagStatus.Partitions {
+		if _, ok := b.TopicThresholds[partition.Topic]; ok && !containsString(topics, partition.Topic) {
+			topics = append(topics, partition.Topic)
+		}
+	}
+	sort.Strings(topics)
+
+	details := []string{s.Details}
+	for _, topic := range topics {
+		topicStatus := checkTopicStatus(group, topic, lagStatus, b.TopicThresholds[topic])
+		if healthchecks.Severity(topicStatus.Result) > healthchecks.Severity(s.Result) {
+			s.Result = topicStatus.Result
+		}
+		details = append(details, topicStatus.Details)
+	}
+	s.Details = strings.Join(details, "; ")
+
+	if b.Trend != nil {
+		s = checkTrend(s, lagStatus.Partitions, *b.Trend)
+	}
+
+	return s
+}
+
+// Get the consumer groups of a cluster from Burrow
+func fetchConsumerGroups(baseUrl string, consumersPath string, apiVersion APIVersion, cluster string) ([]string, error) {
+	if consumersPath == "" {
+		consumersPath = DefaultV2ConsumersPath
+		if apiVersion == APIV3 {
+			consumersPath = DefaultV3ConsumersPath
+		}
+	}
+	path := strings.Replace(consumersPath, "{cluster}", url.PathEscape(cluster), -1)
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s", strings.TrimSuffix(baseUrl, "/"), strings.TrimPrefix(path, "/")), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Callers should close resp.Body when done reading from it
+	// Defer the closing of the body
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Invalid response. Code: %d, Body: %s", resp.StatusCode, responseBody)
+	}
+
+	consumersResponse := consumersResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&consumersResponse)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding json response: %s", err.Error())
+	}
+
+	if consumersResponse.Error {
+		return nil, fmt.Errorf("Burrow returned an error: %s", consumersResponse.Message)
+	}
+
+	return consumersResponse.Consumers, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
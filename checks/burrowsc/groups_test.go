@@ -0,0 +1,223 @@
+package burrowsc
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hootsuite/healthchecks"
+	"github.com/jarcoal/httpmock"
+)
+
+func TestBurrowGroupsStatusChecker_CheckClusterV3(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/v3/kafka/cluster1/consumer",
+		httpmock.NewStringResponder(200, `{"error":false,"message":"consumer list returned","consumers":["groupA","groupB","groupC"]}`))
+	httpmock.RegisterResponder("GET", "http://something.com/v3/kafka/cluster1/consumer/groupA/lag",
+		httpmock.NewStringResponder(200, groupLagResponse("groupA", "OK", "topic1", "OK", 5)))
+	httpmock.RegisterResponder("GET", "http://something.com/v3/kafka/cluster1/consumer/groupB/lag",
+		httpmock.NewStringResponder(200, groupLagResponse("groupB", "ERR", "topic1", "STALL", 20)))
+	httpmock.RegisterResponder("GET", "http://something.com/v3/kafka/cluster1/consumer/groupC/lag",
+		httpmock.NewStringResponder(200, groupLagResponse("groupC", "OK", "topic2", "OK", 500)))
+
+	warningLagThreshold := int64(100)
+	burrowGroupsStatusChecker := BurrowGroupsStatusChecker{
+		BaseUrl:    "http://something.com",
+		APIVersion: APIV3,
+		Cluster:    "cluster1",
+		Thresholds: LagThresholds{Warning: &warningLagThreshold},
+	}
+	status := burrowGroupsStatusChecker.CheckStatus("Consumers")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Consumers",
+				Result:      healthchecks.CRITICAL,
+				Details:     "2 of 3 consumer groups lagging on cluster cluster1: groupB, groupC",
+			},
+			{
+				Description: "groupB",
+				Result:      healthchecks.CRITICAL,
+				Details:     "Consumer group status is ERR, total lag of 20 for group groupB on cluster cluster1",
+			},
+			{
+				Description: "groupC",
+				Result:      healthchecks.WARNING,
+				Details:     "Consumer group status is OK, total lag of 500 for group groupC on cluster cluster1 exceeds warning threshold",
+			},
+			{
+				Description: "groupA",
+				Result:      healthchecks.OK,
+				Details:     "Consumer group status is OK, total lag of 5 for group groupA on cluster cluster1",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
+func TestBurrowGroupsStatusChecker_CheckGroupAndTopicThresholds(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/kafka/cluster1/consumer/groupA/lag",
+		httpmock.NewStringResponder(200, groupLagResponse("groupA", "OK", "topic1", "OK", 50)))
+	httpmock.RegisterResponder("GET", "http://something.com/kafka/cluster1/consumer/groupB/lag",
+		httpmock.NewStringResponder(200, groupLagResponse("groupB", "OK", "topic2", "OK", 50)))
+
+	defaultCritical := int64(10)
+	groupBCritical := int64(1000)
+	topic1Critical := int64(40)
+	burrowGroupsStatusChecker := BurrowGroupsStatusChecker{
+		BaseUrl:         "http://something.com/kafka",
+		Cluster:         "cluster1",
+		ConsumerGroups:  []string{"groupA", "groupB"},
+		Thresholds:      LagThresholds{Critical: &defaultCritical},
+		GroupThresholds: map[string]LagThresholds{"groupA": {}, "groupB": {Critical: &groupBCritical}},
+		TopicThresholds: map[string]LagThresholds{"topic1": {Critical: &topic1Critical}},
+	}
+	status := burrowGroupsStatusChecker.CheckStatus("Consumers")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Consumers",
+				Result:      healthchecks.CRITICAL,
+				Details:     "1 of 2 consumer groups lagging on cluster cluster1: groupA",
+			},
+			{
+				Description: "groupA",
+				Result:      healthchecks.CRITICAL,
+				Details: "Consumer group status is OK, total lag of 50 for group groupA on cluster cluster1; " +
+					"Topic topic1 has total lag of 50 for group groupA on cluster cluster1 exceeds threshold",
+			},
+			{
+				Description: "groupB",
+				Result:      healthchecks.OK,
+				Details:     "Consumer group status is OK, total lag of 50 for group groupB on cluster cluster1",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
+func TestBurrowGroupsStatusChecker_CheckGroupError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/kafka/cluster1/consumer/groupA/lag",
+		httpmock.NewStringResponder(500, "hi"))
+
+	burrowGroupsStatusChecker := BurrowGroupsStatusChecker{
+		BaseUrl:        "http://something.com/kafka",
+		Cluster:        "cluster1",
+		ConsumerGroups: []string{"groupA"},
+	}
+	status := burrowGroupsStatusChecker.CheckStatus("Consumers")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Consumers",
+				Result:      healthchecks.CRITICAL,
+				Details:     "1 of 1 consumer groups lagging on cluster cluster1: groupA",
+			},
+			{
+				Description: "groupA",
+				Result:      healthchecks.CRITICAL,
+				Details:     "Invalid response. Code: 500, Body: hi",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
+func TestBurrowGroupsStatusChecker_CheckListingError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/v3/kafka/cluster1/consumer",
+		httpmock.NewStringResponder(200, `{"error":true,"message":"cluster not found"}`))
+
+	burrowGroupsStatusChecker := BurrowGroupsStatusChecker{
+		BaseUrl:    "http://something.com",
+		APIVersion: APIV3,
+		Cluster:    "cluster1",
+	}
+	status := burrowGroupsStatusChecker.CheckStatus("Consumers")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Consumers",
+				Result:      healthchecks.CRITICAL,
+				Details:     "Burrow returned an error: cluster not found",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
+func TestBurrowGroupsStatusChecker_CheckNoGroups(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/v3/kafka/cluster1/consumer",
+		httpmock.NewStringResponder(200, `{"error":false,"message":"consumer list returned","consumers":[]}`))
+
+	burrowGroupsStatusChecker := BurrowGroupsStatusChecker{
+		BaseUrl:    "http://something.com",
+		APIVersion: APIV3,
+		Cluster:    "cluster1",
+	}
+	status := burrowGroupsStatusChecker.CheckStatus("Consumers")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Consumers",
+				Result:      healthchecks.OK,
+				Details:     "No consumer groups to check on cluster cluster1",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+
+	// The status can be aggregated
+	aggregate := healthchecks.AggregateStatusList([]healthchecks.StatusEndpoint{
+		{Name: "Consumers", Slug: "consumers", Type: "internal", StatusCheck: burrowGroupsStatusChecker},
+	}, "")
+	if aggregate.StatusList[0].Result != healthchecks.OK {
+		t.Errorf("Aggregate result should be `OK`, was: `%s`", aggregate.StatusList[0].Result)
+	}
+}
+
+// Build a lag response of a group consuming a single partition
+func groupLagResponse(group string, groupStatus string, topic string, partitionStatus string, lag int64) string {
+	return fmt.Sprintf(
+		`{"status":{"cluster":"cluster1","group":"%s","status":"%s","partitions":[{"topic":"%s","partition":0,"status":"%s","current_lag":%d}],"totallag":%d}}`,
+		group,
+		groupStatus,
+		topic,
+		partitionStatus,
+		lag,
+		lag,
+	)
+}
@@ -0,0 +1,118 @@
+package burrowsc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// TrendThresholds are evaluated against the lag trend Burrow observed over its evaluation window, estimated from the
+// start and end offsets of each partition. A threshold is disabled when 0.
+//
+// The time behind of a partition is its current lag divided by the rate messages are produced to it, i.e. how long
+// ago the oldest unconsumed message was produced. The lag growth is the rate the lag increased over the window, summed
+// over partitions. Partitions whose window is empty or without progress are left out of the estimation.
+type TrendThresholds struct {
+	WarningTimeBehind  time.Duration // Estimated time behind of the most lagging partition
+	CriticalTimeBehind time.Duration // Estimated time behind of the most lagging partition
+	WarningLagGrowth   float64       // Lag growth in messages per second
+	CriticalLagGrowth  float64       // Lag growth in messages per second
+}
+
+// Estimate the time behind and the lag growth rate, in messages per second, of a partition
+func estimatePartitionTrend(p partition) (time.Duration, float64, bool) {
+	window := float64(p.End.Timestamp-p.Start.Timestamp) / 1000
+	if window <= 0 {
+		return 0, 0, false
+	}
+
+	lagGrowth := float64(p.End.Lag-p.Start.Lag) / window
+
+	// The head of the partition is the committed offset plus the lag, fall back on the consumption rate if no
+	// messages were produced during the window
+	rate := float64((p.End.Offset+p.End.Lag)-(p.Start.Offset+p.Start.Lag)) / window
+	if rate <= 0 {
+		rate = float64(p.End.Offset-p.Start.Offset) / window
+	}
+	if rate <= 0 {
+		return 0, lagGrowth, p.CurrentLag == 0
+	}
+
+	timeBehind := time.Duration(float64(p.CurrentLag) / rate * float64(time.Second))
+	return timeBehind, lagGrowth, true
+}
+
+// Estimate the time behind of the most lagging partition and the total lag growth rate of the partitions
+func estimateTrend(partitions []partition) (time.Duration, float64, bool) {
+	timeBehind := time.Duration(0)
+	lagGrowth := float64(0)
+	estimated := false
+
+	for _, p := range partitions {
+		partitionTimeBehind, partitionLagGrowth, ok := estimatePartitionTrend(p)
+		if !ok {
+			continue
+		}
+
+		estimated = true
+		lagGrowth += partitionLagGrowth
+		if partitionTimeBehind > timeBehind {
+			timeBehind = partitionTimeBehind
+		}
+	}
+
+	return timeBehind, lagGrowth, estimated
+}
+
+// Raise the alert level of a status according to the lag trend of the partitions, appending the trend to its details
+func checkTrend(s healthchecks.Status, partitions []partition, thresholds TrendThresholds) healthchecks.Status {
+	timeBehind, lagGrowth, ok := estimateTrend(partitions)
+	if !ok {
+		return s
+	}
+
+	details := []string{s.Details}
+	raise := func(level healthchecks.AlertLevel, reason string) {
+		if healthchecks.Severity(level) > healthchecks.Severity(s.Result) {
+			s.Result = level
+		}
+		details = append(details, reason)
+	}
+
+	timeBehind = timeBehind.Round(time.Second)
+	behind := fmt.Sprintf("Estimated time behind of %s", timeBehind)
+	if thresholds.CriticalTimeBehind > 0 && timeBehind >= thresholds.CriticalTimeBehind {
+		raise(healthchecks.CRITICAL, fmt.Sprintf("%s exceeds critical threshold of %s", behind, thresholds.CriticalTimeBehind))
+	} else if thresholds.WarningTimeBehind > 0 && timeBehind >= thresholds.WarningTimeBehind {
+		raise(healthchecks.WARNING, fmt.Sprintf("%s exceeds warning threshold of %s", behind, thresholds.WarningTimeBehind))
+	}
+
+	growth := fmt.Sprintf("Lag growth of %.2f messages/s", lagGrowth)
+	if thresholds.CriticalLagGrowth > 0 && lagGrowth >= thresholds.CriticalLagGrowth {
+		raise(healthchecks.CRITICAL, fmt.Sprintf("%s exceeds critical threshold of %.2f messages/s", growth, thresholds.CriticalLagGrowth))
+	} else if thresholds.WarningLagGrowth > 0 && lagGrowth >= thresholds.WarningLagGrowth {
+		raise(healthchecks.WARNING, fmt.Sprintf("%s exceeds warning threshold of %.2f messages/s", growth, thresholds.WarningLagGrowth))
+	}
+
+	details = append(details, fmt.Sprintf("estimated %s behind, lag growing by %.2f messages/s", timeBehind, lagGrowth))
+	s.Details = strings.Join(details, "; ")
+
+	return s
+}
+
+// Get the partitions of a topic, or every partition if topic is nil
+func topicPartitions(partitions []partition, topic *string) []partition {
+	if topic == nil {
+		return partitions
+	}
+
+	filtered := []partition{}
+	for _, p := range partitions {
+		if p.Topic == *topic {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,137 @@
+package burrowsc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+	"github.com/jarcoal/httpmock"
+)
+
+func TestBurrowStatusChecker_CheckGroupTrend(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/v3/kafka/cluster1/consumer/group1/lag",
+		httpmock.NewStringResponder(200, getTestData("v3_status_trend.json")))
+
+	burrowStatusChecker := BurrowStatusChecker{
+		BaseUrl:       "http://something.com",
+		APIVersion:    APIV3,
+		Cluster:       "cluster1",
+		ConsumerGroup: "group1",
+		Trend: &TrendThresholds{
+			WarningTimeBehind:  30 * time.Second,
+			CriticalTimeBehind: time.Minute,
+			WarningLagGrowth:   20,
+		},
+	}
+	status := burrowStatusChecker.CheckStatus("Consumer")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Consumer",
+				Result:      healthchecks.WARNING,
+				Details: "Consumer group status is OK, total lag of 703 for group group1 on cluster cluster1; " +
+					"Estimated time behind of 35s exceeds warning threshold of 30s; " +
+					"estimated 35s behind, lag growing by 10.00 messages/s",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
+func TestBurrowStatusChecker_CheckTopicTrend(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/v3/kafka/cluster1/consumer/group1/lag",
+		httpmock.NewStringResponder(200, getTestData("v3_status_trend.json")))
+
+	// The window of topic2 is empty, its trend can't be estimated
+	topic := "topic2"
+	burrowStatusChecker := BurrowStatusChecker{
+		BaseUrl:       "http://something.com",
+		APIVersion:    APIV3,
+		Cluster:       "cluster1",
+		ConsumerGroup: "group1",
+		Topic:         &topic,
+		Trend:         &TrendThresholds{CriticalLagGrowth: 1},
+	}
+	status := burrowStatusChecker.CheckStatus("Consumer")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Consumer",
+				Result:      healthchecks.OK,
+				Details:     "Topic topic2 has total lag of 3 for group group1 on cluster cluster1",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
+func TestBurrowGroupsStatusChecker_CheckTrend(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/v3/kafka/cluster1/consumer/group1/lag",
+		httpmock.NewStringResponder(200, getTestData("v3_status_trend.json")))
+
+	burrowGroupsStatusChecker := BurrowGroupsStatusChecker{
+		BaseUrl:        "http://something.com",
+		APIVersion:     APIV3,
+		Cluster:        "cluster1",
+		ConsumerGroups: []string{"group1"},
+		Trend:          &TrendThresholds{WarningLagGrowth: 1, CriticalLagGrowth: 5},
+	}
+	status := burrowGroupsStatusChecker.CheckStatus("Consumers")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Consumers",
+				Result:      healthchecks.CRITICAL,
+				Details:     "1 of 1 consumer groups lagging on cluster cluster1: group1",
+			},
+			{
+				Description: "group1",
+				Result:      healthchecks.CRITICAL,
+				Details: "Consumer group status is OK, total lag of 703 for group group1 on cluster cluster1; " +
+					"Lag growth of 10.00 messages/s exceeds critical threshold of 5.00 messages/s; " +
+					"estimated 35s behind, lag growing by 10.00 messages/s",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
+func TestEstimatePartitionTrend(t *testing.T) {
+	p := partition{CurrentLag: 120}
+	p.Start = partitionOffset{Offset: 1000, Timestamp: 1600000000000, Lag: 0}
+	p.End = partitionOffset{Offset: 1000, Timestamp: 1600000060000, Lag: 120}
+
+	// The consumer is stopped while 2 messages/s are produced
+	timeBehind, lagGrowth, ok := estimatePartitionTrend(p)
+	if !ok || timeBehind != time.Minute || lagGrowth != 2 {
+		t.Errorf("Trend should be 1m0s behind growing by 2 messages/s, was %s behind growing by %.2f messages/s (%t)", timeBehind, lagGrowth, ok)
+	}
+
+	// Nothing was produced or consumed, the time behind is unknown
+	p.End = partitionOffset{Offset: 1000, Timestamp: 1600000060000, Lag: 0}
+	if _, _, ok := estimatePartitionTrend(p); ok {
+		t.Errorf("Trend should not be estimated without progress")
+	}
+}
@@ -0,0 +1,225 @@
+package execsc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// Defaults of the ExecStatusChecker
+const (
+	DefaultTimeout        = 10 * time.Second
+	DefaultMaxOutputBytes = 16 * 1024
+)
+
+// Time given to the command to release its output once killed, e.g. by children still holding it
+const waitDelay = time.Second
+
+// TemplateData is the data the arguments and environment of an ExecStatusChecker are rendered with, e.g.
+// `{{.Name}}`, `{{.Vars.host}}` or `{{env "HOME"}}`.
+type TemplateData struct {
+	Name string            // The name of the check
+	Vars map[string]string // The Vars of the ExecStatusChecker
+}
+
+// ExecStatusChecker runs a Nagios / Icinga plugin and maps its exit code to a status: 0 is OK, 1 is WARN, 2 is CRIT and
+// 3 (UNKNOWN) is CRIT. Any other exit code, a command that cannot run or exceeds its timeout is CRIT.
+//
+// The returned StatusList starts with the status of the plugin, its details being the first line of output, followed by
+// one OK entry per performance data metric reporting its value and ranges. The plugin's exit code is the only verdict,
+// the ranges are not evaluated again. Output beyond MaxOutputBytes is discarded.
+type ExecStatusChecker struct {
+	Command        string            // The path of the plugin to run
+	Args           []string          // Optional arguments, rendered as text/template templates with TemplateData
+	Env            map[string]string // Optional environment variables added to the environment of the process, values rendered as Args
+	Vars           map[string]string // Optional variables available to the templates
+	Dir            string            // Optional working directory of the command
+	Timeout        time.Duration     // Optional timeout of the command, defaults to DefaultTimeout
+	MaxOutputBytes int               // Optional maximum number of bytes of output read, defaults to DefaultMaxOutputBytes
+}
+
+func (e ExecStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	s := healthchecks.Status{
+		Description: name,
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	data := TemplateData{Name: name, Vars: e.Vars}
+	args, env, err := e.render(data)
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = err.Error()
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	maxOutputBytes := e.MaxOutputBytes
+	if maxOutputBytes <= 0 {
+		maxOutputBytes = DefaultMaxOutputBytes
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	output := &limitedBuffer{limit: maxOutputBytes}
+	cmd := exec.CommandContext(ctx, e.Command, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Dir = e.Dir
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.WaitDelay = waitDelay
+
+	err = cmd.Run()
+
+	text, perfdata := parseOutput(output.String())
+
+	var exitErr *exec.ExitError
+	switch {
+	case err != nil && ctx.Err() == context.DeadlineExceeded:
+		s.Result = healthchecks.CRITICAL
+		s.Details = fmt.Sprintf("Command timed out after %s", timeout)
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	case errors.As(err, &exitErr):
+		exitCode := exitErr.ExitCode()
+		s.Result = exitCodeResult(exitCode)
+		if exitCode < 0 || exitCode > 3 {
+			text = strings.TrimSuffix(fmt.Sprintf("Unexpected exit code %d; %s", exitCode, text), "; ")
+		}
+	case err != nil:
+		s.Result = healthchecks.CRITICAL
+		s.Details = err.Error()
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	if text == "" {
+		text = "No output"
+	}
+	if output.truncated {
+		text = fmt.Sprintf("%s; output truncated to %d bytes", text, maxOutputBytes)
+	}
+	s.Details = text
+
+	statuses := []healthchecks.Status{s}
+	for _, p := range perfdata {
+		statuses = append(statuses, p.status())
+	}
+
+	return healthchecks.StatusList{StatusList: statuses}
+}
+
+// Render the templated arguments and environment variables
+func (e ExecStatusChecker) render(data TemplateData) ([]string, []string, error) {
+	args := make([]string, 0, len(e.Args))
+	for i, arg := range e.Args {
+		rendered, err := renderTemplate(fmt.Sprintf("arg%d", i), arg, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Error rendering argument `%s`: %s", arg, err.Error())
+		}
+		args = append(args, rendered)
+	}
+
+	env := make([]string, 0, len(e.Env))
+	for key, value := range e.Env {
+		rendered, err := renderTemplate(key, value, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Error rendering environment variable `%s`: %s", key, err.Error())
+		}
+		env = append(env, fmt.Sprintf("%s=%s", key, rendered))
+	}
+
+	return args, env, nil
+}
+
+func renderTemplate(name string, text string, data TemplateData) (string, error) {
+	t, err := template.New(name).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"env": os.Getenv}).
+		Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Map a Nagios plugin exit code to an alert level
+func exitCodeResult(exitCode int) healthchecks.AlertLevel {
+	switch exitCode {
+	case 0:
+		return healthchecks.OK
+	case 1:
+		return healthchecks.WARNING
+	default:
+		return healthchecks.CRITICAL
+	}
+}
+
+// Split the output of a plugin into the text of its first line and the performance data of every line:
+//
+//	TEXT OUTPUT | OPTIONAL PERFDATA
+//	LONG TEXT LINE 1
+//	LONG TEXT LINE 2 | PERFDATA 2
+//	PERFDATA 3
+func parseOutput(output string) (string, []Perfdata) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+
+	text, perfdata := lines[0], ""
+	if i := strings.Index(text, "|"); i >= 0 {
+		text, perfdata = text[:i], text[i+1:]
+	}
+
+	// Performance data of the long text starts after the first `|`
+	inPerfdata := false
+	for _, line := range lines[1:] {
+		if !inPerfdata {
+			i := strings.Index(line, "|")
+			if i < 0 {
+				continue
+			}
+			line = line[i+1:]
+			inPerfdata = true
+		}
+		perfdata += " " + line
+	}
+
+	return strings.TrimSpace(text), ParsePerfdata(perfdata)
+}
+
+// Buffer discarding what is written beyond its limit, without failing the writer. The bytes.Buffer is not embedded
+// so io.Copy cannot bypass the limit through its ReadFrom.
+type limitedBuffer struct {
+	buffer    bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.buffer.Len(); len(p) > remaining {
+		b.truncated = true
+		if remaining > 0 {
+			b.buffer.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	return b.buffer.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buffer.String()
+}
@@ -0,0 +1,133 @@
+package execsc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+func TestExitCodes(t *testing.T) {
+	expected := []struct {
+		script  string
+		result  healthchecks.AlertLevel
+		details string
+	}{
+		{"echo 'DISK OK - free space: / 3326 MB'; exit 0", healthchecks.OK, "DISK OK - free space: / 3326 MB"},
+		{"echo 'DISK WARNING - free space: / 126 MB'; exit 1", healthchecks.WARNING, "DISK WARNING - free space: / 126 MB"},
+		{"echo 'DISK CRITICAL - free space: / 26 MB'; exit 2", healthchecks.CRITICAL, "DISK CRITICAL - free space: / 26 MB"},
+		{"echo 'DISK UNKNOWN - no such mount point'; exit 3", healthchecks.CRITICAL, "DISK UNKNOWN - no such mount point"},
+		{"echo 'Segfault'; exit 139", healthchecks.CRITICAL, "Unexpected exit code 139; Segfault"},
+		{"exit 0", healthchecks.OK, "No output"},
+		{"echo 'To stderr' >&2; exit 1", healthchecks.WARNING, "To stderr"},
+	}
+	for _, e := range expected {
+		statusChecker := ExecStatusChecker{Command: "/bin/sh", Args: []string{"-c", e.script}}
+		s := statusChecker.CheckStatus("The plugin")
+
+		if len(s.StatusList) != 1 {
+			t.Fatalf("Length of StatusList should be 1, was %d", len(s.StatusList))
+		}
+		actual := s.StatusList[0]
+		if actual.Result != e.result || actual.Details != e.details {
+			t.Errorf("Status of `%s` should be `%s` `%s`, was `%s` `%s`", e.script, e.result, e.details, actual.Result, actual.Details)
+		}
+	}
+}
+
+func TestPerfdataOutput(t *testing.T) {
+	script := `echo "DISK OK - free space: / 3326 MB (56%); | /=2643MB;5948;5958;0;5968"
+echo "/ 15272 MB (77%);"
+echo "/boot 68 MB (69%); | /boot=68MB;60;93;0;98"
+echo "/home=69357MB;253404;253409;0;253414"
+echo "'/var log'=818MB;970;975;0;980"`
+
+	statusChecker := ExecStatusChecker{Command: "/bin/sh", Args: []string{"-c", script}}
+	s := statusChecker.CheckStatus("The disk")
+
+	if len(s.StatusList) != 5 {
+		t.Fatalf("Length of StatusList should be 5, was %d: %v", len(s.StatusList), s.StatusList)
+	}
+
+	expected := []healthchecks.Status{
+		{Description: "The disk", Result: healthchecks.OK, Details: "DISK OK - free space: / 3326 MB (56%);"},
+		{Description: "/", Result: healthchecks.OK, Details: "value=2643MB warn=5948 crit=5958 min=0 max=5968"},
+		{Description: "/boot", Result: healthchecks.OK, Details: "value=68MB warn=60 crit=93 min=0 max=98"},
+		{Description: "/home", Result: healthchecks.OK, Details: "value=69357MB warn=253404 crit=253409 min=0 max=253414"},
+		{Description: "/var log", Result: healthchecks.OK, Details: "value=818MB warn=970 crit=975 min=0 max=980"},
+	}
+
+	for i, e := range expected {
+		if s.StatusList[i] != e {
+			t.Errorf("Status should be `%v`, was `%v`", e, s.StatusList[i])
+		}
+	}
+}
+
+func TestTemplating(t *testing.T) {
+	statusChecker := ExecStatusChecker{
+		Command: "/bin/sh",
+		Args:    []string{"-c", `echo "$1 $CHECK_HOST $CHECK_NAME"`, "sh", "{{.Vars.host}}:{{.Vars.port}}"},
+		Env: map[string]string{
+			"CHECK_HOST": "--host={{.Vars.host}}",
+			"CHECK_NAME": "{{.Name}}",
+		},
+		Vars: map[string]string{"host": "db.example.com", "port": "5432"},
+	}
+	actual := statusChecker.CheckStatus("The database").StatusList[0]
+
+	eDetails := "db.example.com:5432 --host=db.example.com The database"
+	if actual.Result != healthchecks.OK || actual.Details != eDetails {
+		t.Errorf("Status should be `OK` `%s`, was `%s` `%s`", eDetails, actual.Result, actual.Details)
+	}
+}
+
+func TestTemplatingError(t *testing.T) {
+	statusChecker := ExecStatusChecker{Command: "/bin/echo", Args: []string{"{{.Vars.missing}}"}, Vars: map[string]string{}}
+	actual := statusChecker.CheckStatus("The plugin").StatusList[0]
+
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+	if !strings.HasPrefix(actual.Details, "Error rendering argument `{{.Vars.missing}}`: ") {
+		t.Errorf("Details should start with `Error rendering argument`, was `%s`", actual.Details)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	statusChecker := ExecStatusChecker{Command: "/bin/sh", Args: []string{"-c", "sleep 10"}, Timeout: 100 * time.Millisecond}
+
+	start := time.Now()
+	actual := statusChecker.CheckStatus("The plugin").StatusList[0]
+
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("CheckStatus should return shortly after the timeout, took %s", time.Since(start))
+	}
+	eDetails := "Command timed out after 100ms"
+	if actual.Result != healthchecks.CRITICAL || actual.Details != eDetails {
+		t.Errorf("Status should be `CRITICAL` `%s`, was `%s` `%s`", eDetails, actual.Result, actual.Details)
+	}
+}
+
+func TestOutputFlood(t *testing.T) {
+	statusChecker := ExecStatusChecker{
+		Command:        "/bin/sh",
+		Args:           []string{"-c", "echo 'FLOOD OK'; yes | head -c 1000000; exit 0"},
+		MaxOutputBytes: 64,
+	}
+	actual := statusChecker.CheckStatus("The plugin").StatusList[0]
+
+	eDetails := "FLOOD OK; output truncated to 64 bytes"
+	if actual.Result != healthchecks.OK || actual.Details != eDetails {
+		t.Errorf("Status should be `OK` `%s`, was `%s` `%s`", eDetails, actual.Result, actual.Details)
+	}
+}
+
+func TestCommandNotFound(t *testing.T) {
+	actual := ExecStatusChecker{Command: "/does/not/exist"}.CheckStatus("The plugin").StatusList[0]
+
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+}
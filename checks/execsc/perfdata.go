@@ -0,0 +1,97 @@
+package execsc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// Perfdata is a performance data metric of a Nagios plugin: `'label'=value[UOM];[warn];[crit];[min];[max]`
+type Perfdata struct {
+	Label    string
+	Value    float64
+	UOM      string // Unit of measurement, e.g. `s`, `%`, `B` or `c`
+	Warning  string // Warning range, in the Nagios range format e.g. `10`, `10:`, `~:10`, `10:20` or `@10:20`
+	Critical string // Critical range, in the same format as Warning
+	Min      string
+	Max      string
+}
+
+// ParsePerfdata parses space separated performance data metrics, skipping the malformed ones and the ones with an
+// unknown `U` value
+func ParsePerfdata(perfdata string) []Perfdata {
+	metrics := []Perfdata{}
+	for _, field := range splitPerfdata(perfdata) {
+		i := strings.LastIndex(field, "=")
+		if i <= 0 {
+			continue
+		}
+
+		label := strings.Replace(strings.Trim(field[:i], "'"), "''", "'", -1)
+		values := strings.Split(field[i+1:], ";")
+		for len(values) < 5 {
+			values = append(values, "")
+		}
+
+		number := strings.TrimRight(values[0], "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ%")
+		value, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			continue
+		}
+
+		metrics = append(metrics, Perfdata{
+			Label:    label,
+			Value:    value,
+			UOM:      values[0][len(number):],
+			Warning:  values[1],
+			Critical: values[2],
+			Min:      values[3],
+			Max:      values[4],
+		})
+	}
+	return metrics
+}
+
+// Split performance data on spaces outside of quoted labels
+func splitPerfdata(perfdata string) []string {
+	fields := []string{}
+	var field strings.Builder
+	quoted := false
+	for _, r := range perfdata {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+			field.WriteRune(r)
+		case r == ' ' && !quoted:
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteRune(r)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// Get the status of the metric, reporting its value and ranges. The plugin evaluates its own ranges and reports the
+// verdict through its exit code, so the status is always OK.
+func (p Perfdata) status() healthchecks.Status {
+	details := fmt.Sprintf("value=%s%s", strconv.FormatFloat(p.Value, 'f', -1, 64), p.UOM)
+	for _, threshold := range []struct{ name, value string }{{"warn", p.Warning}, {"crit", p.Critical}, {"min", p.Min}, {"max", p.Max}} {
+		if threshold.value != "" {
+			details += fmt.Sprintf(" %s=%s", threshold.name, threshold.value)
+		}
+	}
+
+	return healthchecks.Status{
+		Description: p.Label,
+		Result:      healthchecks.OK,
+		Details:     details,
+	}
+}
@@ -0,0 +1,30 @@
+package execsc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePerfdata(t *testing.T) {
+	actual := ParsePerfdata(`time=0.012s;1;5;0 'free space'=85% size=U;;; 'it''s'=3c broken =1 load1=0.5`)
+
+	expected := []Perfdata{
+		{Label: "time", Value: 0.012, UOM: "s", Warning: "1", Critical: "5", Min: "0"},
+		{Label: "free space", Value: 85, UOM: "%"},
+		{Label: "it's", Value: 3, UOM: "c"},
+		{Label: "load1", Value: 0.5},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Perfdata should be `%v`, was `%v`", expected, actual)
+	}
+}
+
+func TestPerfdataStatus(t *testing.T) {
+	p := Perfdata{Label: "time", Value: 6, UOM: "s", Warning: "1", Critical: "5"}
+	actual := p.status()
+
+	eDetails := "value=6s warn=1 crit=5"
+	if actual.Description != "time" || actual.Details != eDetails || actual.Result != "OK" {
+		t.Errorf("Status should be `time` `OK` `%s`, was `%v`", eDetails, actual)
+	}
+}
@@ -0,0 +1,64 @@
+package grpcsc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Default timeout of the Health/Check call
+const DefaultTimeout = 5 * time.Second
+
+// GrpcStatusChecker checks a remote service implementing the gRPC health checking protocol (grpc.health.v1.Health).
+//
+// SERVING is reported as OK, UNKNOWN as WARN, and NOT_SERVING, SERVICE_UNKNOWN or a failed call as CRIT.
+type GrpcStatusChecker struct {
+	Conn    grpc.ClientConnInterface // Connection to the remote service
+	Service string                   // Optional service name to check, leave empty to check the overall server status
+	Timeout time.Duration            // Optional timeout of the call, defaults to 5 seconds
+}
+
+func (g GrpcStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	timeout := g.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	s := healthchecks.Status{
+		Description: name,
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	resp, err := grpc_health_v1.NewHealthClient(g.Conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: g.Service})
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		if status.Code(err) == codes.NotFound {
+			s.Details = fmt.Sprintf("Service `%s` is unknown to the remote health server", g.Service)
+		} else {
+			s.Details = err.Error()
+		}
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	switch resp.GetStatus() {
+	case grpc_health_v1.HealthCheckResponse_SERVING:
+	case grpc_health_v1.HealthCheckResponse_UNKNOWN:
+		s.Result = healthchecks.WARNING
+		s.Details = "Serving status is UNKNOWN"
+	default:
+		s.Result = healthchecks.CRITICAL
+		s.Details = fmt.Sprintf("Serving status is %s", resp.GetStatus())
+	}
+
+	return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+}
@@ -0,0 +1,83 @@
+package grpcsc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/hootsuite/healthchecks"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGrpcStatusChecker_CheckStatus(t *testing.T) {
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("serving", grpc_health_v1.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("not-serving", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus("unknown", grpc_health_v1.HealthCheckResponse_UNKNOWN)
+
+	conn, closeFunc := startServer(t, healthServer)
+	defer closeFunc()
+
+	tests := []struct {
+		service  string
+		expected healthchecks.Status
+	}{
+		{"", healthchecks.Status{Description: "Remote", Result: healthchecks.OK, Details: ""}},
+		{"serving", healthchecks.Status{Description: "Remote", Result: healthchecks.OK, Details: ""}},
+		{"not-serving", healthchecks.Status{Description: "Remote", Result: healthchecks.CRITICAL, Details: "Serving status is NOT_SERVING"}},
+		{"unknown", healthchecks.Status{Description: "Remote", Result: healthchecks.WARNING, Details: "Serving status is UNKNOWN"}},
+		{"missing", healthchecks.Status{Description: "Remote", Result: healthchecks.CRITICAL, Details: "Service `missing` is unknown to the remote health server"}},
+	}
+
+	for _, test := range tests {
+		grpcStatusChecker := GrpcStatusChecker{Conn: conn, Service: test.service}
+		s := grpcStatusChecker.CheckStatus("Remote")
+
+		if len(s.StatusList) != 1 {
+			t.Errorf("Length of StatusList should be 1, was %d", len(s.StatusList))
+		}
+
+		if s.StatusList[0] != test.expected {
+			t.Errorf("Status of `%s` should be `%v`, was `%v`", test.service, test.expected, s.StatusList[0])
+		}
+	}
+}
+
+func TestGrpcStatusChecker_CheckStatusUnavailable(t *testing.T) {
+	conn, closeFunc := startServer(t, health.NewServer())
+	closeFunc()
+
+	grpcStatusChecker := GrpcStatusChecker{Conn: conn}
+	s := grpcStatusChecker.CheckStatus("Remote")
+
+	if s.StatusList[0].Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRIT`, was `%s`", s.StatusList[0].Result)
+	}
+}
+
+func startServer(t *testing.T, healthServer grpc_health_v1.HealthServer) (*grpc.ClientConn, func()) {
+	listener := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(s, healthServer)
+	go s.Serve(listener)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Error dialing bufconn server: %s", err)
+	}
+
+	return conn, func() {
+		conn.Close()
+		s.Stop()
+	}
+}
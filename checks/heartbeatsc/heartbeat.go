@@ -0,0 +1,117 @@
+package heartbeatsc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// Default numbers of missed intervals from which the HeartbeatStatusChecker raises alerts
+const (
+	DefaultWarningMissed  = 1
+	DefaultCriticalMissed = 3
+)
+
+// Heartbeat records the last beat of a job, such as a worker loop or a consumer. It is safe for concurrent use.
+type Heartbeat struct {
+	name     string
+	now      func() time.Time
+	mu       sync.RWMutex
+	started  time.Time
+	lastBeat time.Time
+}
+
+// NewHeartbeat creates a heartbeat using the system clock. Missed intervals are counted from its creation until the
+// first beat.
+func NewHeartbeat(name string) *Heartbeat {
+	return NewHeartbeatWithClock(name, time.Now)
+}
+
+// NewHeartbeatWithClock creates a heartbeat reading the time from now, used for tests
+func NewHeartbeatWithClock(name string, now func() time.Time) *Heartbeat {
+	return &Heartbeat{
+		name:    name,
+		now:     now,
+		started: now(),
+	}
+}
+
+// Name gets the name of the heartbeat
+func (h *Heartbeat) Name() string {
+	return h.name
+}
+
+// Beat records a beat at the current time
+func (h *Heartbeat) Beat() {
+	now := h.now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastBeat = now
+}
+
+// LastBeat gets the time of the last beat, zero if it never beat
+func (h *Heartbeat) LastBeat() time.Time {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.lastBeat
+}
+
+// HeartbeatStatusChecker checks that a Heartbeat beats at least once every Interval. The status is WARN once
+// WarningMissed intervals elapsed without a beat, and CRITICAL once CriticalMissed intervals did.
+type HeartbeatStatusChecker struct {
+	Heartbeat      *Heartbeat    // The heartbeat to check
+	Interval       time.Duration // The expected interval between beats
+	WarningMissed  int           // Optional number of missed intervals to warn from, defaults to DefaultWarningMissed
+	CriticalMissed int           // Optional number of missed intervals to raise a critical alert from, defaults to DefaultCriticalMissed
+}
+
+func (h HeartbeatStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	s := healthchecks.Status{
+		Description: name,
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	if h.Heartbeat == nil || h.Interval <= 0 {
+		s.Result = healthchecks.CRITICAL
+		s.Details = "HeartbeatStatusChecker requires a Heartbeat and a positive Interval"
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	warningMissed := h.WarningMissed
+	if warningMissed <= 0 {
+		warningMissed = DefaultWarningMissed
+	}
+	criticalMissed := h.CriticalMissed
+	if criticalMissed <= 0 {
+		criticalMissed = DefaultCriticalMissed
+	}
+
+	now := h.Heartbeat.now()
+	lastBeat := h.Heartbeat.LastBeat()
+
+	since := h.Heartbeat.started
+	lastBeatDetails := "last_beat=never"
+	if !lastBeat.IsZero() {
+		since = lastBeat
+		lastBeatDetails = fmt.Sprintf("last_beat=%s (%s ago)", lastBeat.Format(time.RFC3339), now.Sub(lastBeat).Truncate(time.Second))
+	}
+
+	missed := int(now.Sub(since) / h.Interval)
+	details := fmt.Sprintf("heartbeat=%s %s missed_intervals=%d interval=%s", h.Heartbeat.name, lastBeatDetails, missed, h.Interval)
+
+	if missed >= criticalMissed {
+		s.Result = healthchecks.CRITICAL
+		s.Details = fmt.Sprintf("Missed %d intervals, exceeds critical threshold of %d; %s", missed, criticalMissed, details)
+	} else if missed >= warningMissed {
+		s.Result = healthchecks.WARNING
+		s.Details = fmt.Sprintf("Missed %d intervals, exceeds warning threshold of %d; %s", missed, warningMissed, details)
+	} else {
+		s.Details = details
+	}
+
+	return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+}
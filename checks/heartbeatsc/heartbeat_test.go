@@ -0,0 +1,110 @@
+package heartbeatsc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+func TestHeartbeat(t *testing.T) {
+	clock := &MockClock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+	heartbeat := NewHeartbeatWithClock("consumer", clock.Now)
+	statusChecker := HeartbeatStatusChecker{Heartbeat: heartbeat, Interval: time.Minute}
+
+	clock.Advance(30 * time.Second)
+	heartbeat.Beat()
+	clock.Advance(40 * time.Second)
+
+	expected := []struct {
+		advance time.Duration
+		result  healthchecks.AlertLevel
+		details string
+	}{
+		{0, healthchecks.OK, "heartbeat=consumer last_beat=2020-01-01T12:00:30Z (40s ago) missed_intervals=0 interval=1m0s"},
+		{30 * time.Second, healthchecks.WARNING, "Missed 1 intervals, exceeds warning threshold of 1; heartbeat=consumer last_beat=2020-01-01T12:00:30Z (1m10s ago) missed_intervals=1 interval=1m0s"},
+		{2 * time.Minute, healthchecks.CRITICAL, "Missed 3 intervals, exceeds critical threshold of 3; heartbeat=consumer last_beat=2020-01-01T12:00:30Z (3m10s ago) missed_intervals=3 interval=1m0s"},
+	}
+	for _, e := range expected {
+		clock.Advance(e.advance)
+		s := statusChecker.CheckStatus("The consumer")
+
+		if len(s.StatusList) != 1 {
+			t.Fatalf("Length of StatusList should be 1, was %d", len(s.StatusList))
+		}
+		actual := s.StatusList[0]
+		if actual.Result != e.result {
+			t.Errorf("Result should be `%s`, was `%s`", e.result, actual.Result)
+		}
+		if actual.Details != e.details {
+			t.Errorf("Details should be `%s`, was `%s`", e.details, actual.Details)
+		}
+	}
+
+	// Beating again recovers
+	heartbeat.Beat()
+	actual := statusChecker.CheckStatus("The consumer").StatusList[0]
+	if actual.Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was `%s`", actual.Result)
+	}
+}
+
+func TestHeartbeatNeverBeat(t *testing.T) {
+	clock := &MockClock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+	heartbeat := NewHeartbeatWithClock("worker", clock.Now)
+	statusChecker := HeartbeatStatusChecker{Heartbeat: heartbeat, Interval: time.Minute, WarningMissed: 2, CriticalMissed: 5}
+
+	clock.Advance(2 * time.Minute)
+	actual := statusChecker.CheckStatus("The worker").StatusList[0]
+
+	if actual.Result != healthchecks.WARNING {
+		t.Errorf("Result should be `WARNING`, was `%s`", actual.Result)
+	}
+	eDetails := "Missed 2 intervals, exceeds warning threshold of 2; heartbeat=worker last_beat=never missed_intervals=2 interval=1m0s"
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestHeartbeatInvalid(t *testing.T) {
+	actual := HeartbeatStatusChecker{Interval: time.Minute}.CheckStatus("The worker").StatusList[0]
+
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+}
+
+func TestHeartbeatSystemClock(t *testing.T) {
+	heartbeat := NewHeartbeat("worker")
+	if !heartbeat.LastBeat().IsZero() {
+		t.Errorf("LastBeat should be zero before the first beat")
+	}
+
+	heartbeat.Beat()
+	if time.Since(heartbeat.LastBeat()) > time.Second {
+		t.Errorf("LastBeat should be now, was `%s`", heartbeat.LastBeat())
+	}
+	if heartbeat.Name() != "worker" {
+		t.Errorf("Name should be `worker`, was `%s`", heartbeat.Name())
+	}
+}
+
+// Mocks
+
+type MockClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *MockClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *MockClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,293 @@
+package httpsc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// Default number of response body bytes read when evaluating body assertions
+const defaultMaxBodyBytes = 1 << 20
+
+// JSONPathAssertion asserts on a single value of a JSON response body.
+//
+// Path is a simple JSONPath expression such as `$.status`, `$.nodes[0].name` or `$['cluster-name']`.
+// If Expected is empty the assertion only checks that the path exists, otherwise the value found at the
+// path is formatted as a string (numbers in their shortest form, objects and arrays as JSON) and compared
+// with Expected.
+type JSONPathAssertion struct {
+	Path     string
+	Expected string
+}
+
+// EndpointStatusChecker probes an arbitrary HTTP endpoint that does not implement the healthchecks protocol.
+//
+// A failed request, an unexpected status code or a failed body assertion results in a CRITICAL status. Every
+// failed assertion is reported in the details. Latency thresholds are only evaluated when every assertion passed.
+type EndpointStatusChecker struct {
+	Url                 string              // The URL to probe
+	Method              string              // Optional HTTP method, defaults to GET
+	Body                string              // Optional request body
+	Headers             map[string]string   // Optional request headers
+	Timeout             time.Duration       // Optional request timeout, also applied with Client, no timeout if 0
+	ExpectedStatusCodes []int               // Optional list of accepted status codes, defaults to any 2xx code
+	BodyRegex           string              // Optional regular expression the response body must match
+	JSONPathAssertions  []JSONPathAssertion // Optional assertions on a JSON response body
+	WarningLatency      time.Duration       // Optional latency above which a warning is raised
+	CriticalLatency     time.Duration       // Optional latency above which a critical alert is raised
+	MaxBodyBytes        int64               // Optional maximum number of body bytes read, defaults to 1 MiB
+	Client              *http.Client        // Optional client used for the request, its own Timeout still applies
+}
+
+func (e EndpointStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	method := e.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if e.Body != "" {
+		body = strings.NewReader(e.Body)
+	}
+
+	// Apply the timeout through the request context, so it also holds with a caller-supplied client
+	ctx := context.Background()
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, e.Url, body)
+	if err != nil {
+		return endpointStatus(name, healthchecks.CRITICAL, err.Error())
+	}
+
+	for key, value := range e.Headers {
+		req.Header.Set(key, value)
+	}
+
+	client := e.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return endpointStatus(name, healthchecks.CRITICAL, err.Error())
+	}
+
+	// Callers should close resp.Body when done reading from it
+	// Defer the closing of the body
+	defer resp.Body.Close()
+
+	maxBodyBytes := e.MaxBodyBytes
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultMaxBodyBytes
+	}
+
+	responseBody, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	latency := time.Since(start)
+	if err != nil {
+		return endpointStatus(name, healthchecks.CRITICAL, fmt.Sprintf("Error reading response body: %s", err.Error()))
+	}
+
+	failures := []string{}
+
+	if !e.isExpectedStatusCode(resp.StatusCode) {
+		failures = append(failures, fmt.Sprintf("Unexpected status code %d", resp.StatusCode))
+	}
+
+	if e.BodyRegex != "" {
+		re, err := regexp.Compile(e.BodyRegex)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("Invalid body regex `%s`: %s", e.BodyRegex, err.Error()))
+		} else if !re.Match(responseBody) {
+			failures = append(failures, fmt.Sprintf("Body does not match `%s`", e.BodyRegex))
+		}
+	}
+
+	if len(e.JSONPathAssertions) > 0 {
+		var document interface{}
+		if err := json.Unmarshal(responseBody, &document); err != nil {
+			failures = append(failures, fmt.Sprintf("Error decoding json response: %s", err.Error()))
+		} else {
+			for _, assertion := range e.JSONPathAssertions {
+				if failure := assertion.evaluate(document); failure != "" {
+					failures = append(failures, failure)
+				}
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return endpointStatus(name, healthchecks.CRITICAL, strings.Join(failures, "; "))
+	}
+
+	latencyDetails := fmt.Sprintf("Response time of %s", latency)
+
+	if e.CriticalLatency > 0 && latency > e.CriticalLatency {
+		return endpointStatus(name, healthchecks.CRITICAL, fmt.Sprintf("%s exceeds threshold of %s", latencyDetails, e.CriticalLatency))
+	}
+
+	if e.WarningLatency > 0 && latency > e.WarningLatency {
+		return endpointStatus(name, healthchecks.WARNING, fmt.Sprintf("%s exceeds threshold of %s", latencyDetails, e.WarningLatency))
+	}
+
+	return endpointStatus(name, healthchecks.OK, "")
+}
+
+func (e EndpointStatusChecker) isExpectedStatusCode(statusCode int) bool {
+	if len(e.ExpectedStatusCodes) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+
+	for _, expected := range e.ExpectedStatusCodes {
+		if statusCode == expected {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Evaluate the assertion against the decoded JSON document and return a description of the failure,
+// or an empty string if the assertion passed
+func (a JSONPathAssertion) evaluate(document interface{}) string {
+	value, err := lookupJSONPath(document, a.Path)
+	if err != nil {
+		return fmt.Sprintf("JSONPath %s: %s", a.Path, err.Error())
+	}
+
+	if a.Expected == "" {
+		return ""
+	}
+
+	actual := formatJSONValue(value)
+	if actual != a.Expected {
+		return fmt.Sprintf("JSONPath %s: expected `%s`, got `%s`", a.Path, a.Expected, actual)
+	}
+
+	return ""
+}
+
+// Resolve a simple JSONPath expression supporting `$`, `.key`, `['key']` and `[index]` segments
+func lookupJSONPath(document interface{}, path string) (interface{}, error) {
+	p := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	current := document
+
+	for len(p) > 0 {
+		switch {
+		case p[0] == '.':
+			p = p[1:]
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			key := p[:end]
+			p = p[end:]
+			if key == "" {
+				return nil, fmt.Errorf("empty key")
+			}
+
+			var err error
+			if current, err = lookupJSONKey(current, key); err != nil {
+				return nil, err
+			}
+		case p[0] == '[':
+			end := strings.Index(p, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated `[`")
+			}
+			segment := p[1:end]
+			p = p[end+1:]
+
+			var err error
+			if len(segment) >= 2 && (segment[0] == '\'' || segment[0] == '"') && segment[len(segment)-1] == segment[0] {
+				current, err = lookupJSONKey(current, segment[1:len(segment)-1])
+			} else {
+				current, err = lookupJSONIndex(current, segment)
+			}
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("invalid path segment `%s`", p)
+		}
+	}
+
+	return current, nil
+}
+
+func lookupJSONKey(current interface{}, key string) (interface{}, error) {
+	object, ok := current.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot look up key `%s` in a non-object value", key)
+	}
+
+	value, ok := object[key]
+	if !ok {
+		return nil, fmt.Errorf("key `%s` not found", key)
+	}
+
+	return value, nil
+}
+
+func lookupJSONIndex(current interface{}, segment string) (interface{}, error) {
+	index, err := strconv.Atoi(segment)
+	if err != nil {
+		return nil, fmt.Errorf("invalid index `%s`", segment)
+	}
+
+	array, ok := current.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot look up index %d in a non-array value", index)
+	}
+
+	if index < 0 || index >= len(array) {
+		return nil, fmt.Errorf("index %d out of range", index)
+	}
+
+	return array[index], nil
+}
+
+func formatJSONValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(b)
+	}
+}
+
+func endpointStatus(name string, result healthchecks.AlertLevel, details string) healthchecks.StatusList {
+	return healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: name,
+				Result:      result,
+				Details:     details,
+			},
+		},
+	}
+}
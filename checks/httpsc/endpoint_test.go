@@ -0,0 +1,210 @@
+package httpsc
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+	"github.com/jarcoal/httpmock"
+)
+
+func TestEndpointStatusChecker_CheckStatusOK(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/_cluster/health",
+		httpmock.NewStringResponder(200, `{"status":"green","nodes":[{"name":"node-1","shards":12}]}`))
+
+	endpointStatusChecker := EndpointStatusChecker{
+		Url:       "http://something.com/_cluster/health",
+		BodyRegex: `"status":"(green|yellow)"`,
+		JSONPathAssertions: []JSONPathAssertion{
+			{Path: "$.status", Expected: "green"},
+			{Path: "$.nodes[0].shards", Expected: "12"},
+			{Path: "$.nodes[0]['name']"},
+		},
+	}
+	status := endpointStatusChecker.CheckStatus("Elasticsearch")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Elasticsearch",
+				Result:      healthchecks.OK,
+				Details:     "",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
+func TestEndpointStatusChecker_CheckStatusMethodAndBody(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "http://something.com/graphql",
+		func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("Content-Type") != "application/json" {
+				return httpmock.NewStringResponse(415, ""), nil
+			}
+			return httpmock.NewStringResponse(201, `{"data":{"ping":true}}`), nil
+		})
+
+	endpointStatusChecker := EndpointStatusChecker{
+		Url:                 "http://something.com/graphql",
+		Method:              "POST",
+		Body:                `{"query":"{ ping }"}`,
+		Headers:             map[string]string{"Content-Type": "application/json"},
+		ExpectedStatusCodes: []int{200, 201},
+		JSONPathAssertions:  []JSONPathAssertion{{Path: "$.data.ping", Expected: "true"}},
+	}
+	status := endpointStatusChecker.CheckStatus("GraphQL")
+
+	if status.StatusList[0].Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was: `%v`", status)
+	}
+}
+
+func TestEndpointStatusChecker_CheckStatusFailedAssertions(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/health",
+		httpmock.NewStringResponder(503, `{"status":"red","nodes":[]}`))
+
+	endpointStatusChecker := EndpointStatusChecker{
+		Url:       "http://something.com/health",
+		BodyRegex: "green",
+		JSONPathAssertions: []JSONPathAssertion{
+			{Path: "$.status", Expected: "green"},
+			{Path: "$.nodes[0].name"},
+		},
+	}
+	status := endpointStatusChecker.CheckStatus("Legacy")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Legacy",
+				Result:      healthchecks.CRITICAL,
+				Details:     "Unexpected status code 503; Body does not match `green`; JSONPath $.status: expected `green`, got `red`; JSONPath $.nodes[0].name: index 0 out of range",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
+func TestEndpointStatusChecker_CheckStatusInvalidJson(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/health",
+		httpmock.NewStringResponder(200, `hi`))
+
+	endpointStatusChecker := EndpointStatusChecker{
+		Url:                "http://something.com/health",
+		JSONPathAssertions: []JSONPathAssertion{{Path: "$.status", Expected: "green"}},
+	}
+	status := endpointStatusChecker.CheckStatus("Legacy")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Legacy",
+				Result:      healthchecks.CRITICAL,
+				Details:     "Error decoding json response: invalid character 'h' looking for beginning of value",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
+func TestEndpointStatusChecker_CheckStatusLatency(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/slow",
+		func(req *http.Request) (*http.Response, error) {
+			time.Sleep(20 * time.Millisecond)
+			return httpmock.NewStringResponse(200, "OK"), nil
+		})
+
+	warnChecker := EndpointStatusChecker{
+		Url:             "http://something.com/slow",
+		WarningLatency:  time.Millisecond,
+		CriticalLatency: time.Minute,
+	}
+	status := warnChecker.CheckStatus("Slow")
+	if status.StatusList[0].Result != healthchecks.WARNING {
+		t.Errorf("Result should be `WARN`, was: `%v`", status)
+	}
+
+	critChecker := EndpointStatusChecker{
+		Url:             "http://something.com/slow",
+		WarningLatency:  time.Millisecond,
+		CriticalLatency: 2 * time.Millisecond,
+	}
+	status = critChecker.CheckStatus("Slow")
+	if status.StatusList[0].Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRIT`, was: `%v`", status)
+	}
+}
+
+func TestEndpointStatusChecker_CheckStatusTimeoutWithClient(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/hanging",
+		func(req *http.Request) (*http.Response, error) {
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(time.Second):
+				return httpmock.NewStringResponse(200, "OK"), nil
+			}
+		})
+
+	endpointStatusChecker := EndpointStatusChecker{
+		Url:     "http://something.com/hanging",
+		Timeout: 10 * time.Millisecond,
+		Client:  &http.Client{},
+	}
+	status := endpointStatusChecker.CheckStatus("Hanging")
+
+	if status.StatusList[0].Result != healthchecks.CRITICAL || !strings.Contains(status.StatusList[0].Details, "context deadline exceeded") {
+		t.Errorf("Status should be `CRIT` with a timeout, was: `%v`", status)
+	}
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	document := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b-c": []interface{}{"x", map[string]interface{}{"d": 1.5}},
+		},
+	}
+
+	value, err := lookupJSONPath(document, "$.a['b-c'][1].d")
+	if err != nil || value != 1.5 {
+		t.Errorf("Value should be `1.5`, was: `%v` (error: %v)", value, err)
+	}
+
+	if _, err := lookupJSONPath(document, "$.a.missing"); err == nil {
+		t.Errorf("Looking up a missing key should fail")
+	}
+
+	if _, err := lookupJSONPath(document, "$.a[0]"); err == nil {
+		t.Errorf("Looking up an index in an object should fail")
+	}
+}
@@ -4,16 +4,83 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/hootsuite/healthchecks"
+	"github.com/hootsuite/healthchecks/checks/tlssc"
 	"io/ioutil"
+	"net"
 	"net/http"
+	neturl "net/url"
 	"strings"
 )
 
 type HttpStatusChecker struct {
-	BaseUrl string
+	BaseUrl     string
+	Certificate *tlssc.CertificateStatusChecker // Optional, also checks the certificate of an https BaseUrl when set
 }
 
+// CheckStatus checks the aggregate status of the service and, when Certificate is set, its certificate. The worse of
+// both results and the details of both are reported in the first status.
 func (h HttpStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	s := h.checkAggregateStatus(name)
+
+	if h.Certificate != nil {
+		certificateStatus := h.checkCertificate(name).StatusList[0]
+		if healthchecks.Severity(certificateStatus.Result) > healthchecks.Severity(s.StatusList[0].Result) {
+			s.StatusList[0].Result = certificateStatus.Result
+		}
+
+		details := []string{}
+		if s.StatusList[0].Details != "" {
+			details = append(details, s.StatusList[0].Details)
+		}
+		details = append(details, fmt.Sprintf("Certificate: %s", certificateStatus.Details))
+		s.StatusList[0].Details = strings.Join(details, "; ")
+	}
+
+	return s
+}
+
+// Check the certificate of the BaseUrl, its host is used unless the certificate checker sets an Address or PEMFile
+func (h HttpStatusChecker) checkCertificate(name string) healthchecks.StatusList {
+	certificateChecker := *h.Certificate
+	certificateName := fmt.Sprintf("%s certificate", name)
+
+	if certificateChecker.Address == "" && certificateChecker.PEMFile == "" {
+		u, err := neturl.Parse(h.BaseUrl)
+		if err != nil {
+			return healthchecks.StatusList{
+				StatusList: []healthchecks.Status{
+					{
+						Description: certificateName,
+						Result:      healthchecks.CRITICAL,
+						Details:     err.Error(),
+					},
+				},
+			}
+		}
+
+		if u.Scheme != "https" {
+			return healthchecks.StatusList{
+				StatusList: []healthchecks.Status{
+					{
+						Description: certificateName,
+						Result:      healthchecks.CRITICAL,
+						Details:     fmt.Sprintf("No certificate to check, %s is not an https url", h.BaseUrl),
+					},
+				},
+			}
+		}
+
+		port := u.Port()
+		if port == "" {
+			port = "443"
+		}
+		certificateChecker.Address = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	return certificateChecker.CheckStatus(certificateName)
+}
+
+func (h HttpStatusChecker) checkAggregateStatus(name string) healthchecks.StatusList {
 	baseUrl := strings.TrimSuffix(h.BaseUrl, "/")
 	url := fmt.Sprintf("%s/status/aggregate", baseUrl)
 	req, err := http.NewRequest("GET", url, nil)
@@ -1,9 +1,14 @@
 package httpsc
 
 import (
-	"github.com/jarcoal/httpmock"
+	"crypto/x509"
 	"github.com/hootsuite/healthchecks"
+	"github.com/hootsuite/healthchecks/checks/tlssc"
+	"github.com/jarcoal/httpmock"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -158,6 +163,80 @@ func TestHttpStatusChecker_CheckStatusTrimBaseUrl(t *testing.T) {
 	}
 }
 
+func TestHttpStatusChecker_CheckStatusCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// The aggregate request is mocked, the certificate is read from the test server
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", server.URL+"/status/aggregate",
+		httpmock.NewStringResponder(200, `["OK"]`))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	httpStatusChecker := HttpStatusChecker{BaseUrl: server.URL, Certificate: &tlssc.CertificateStatusChecker{Roots: roots}}
+	status := httpStatusChecker.CheckStatus("Service name")
+
+	if len(status.StatusList) != 1 {
+		t.Fatalf("Length of StatusList should be 1, was %d", len(status.StatusList))
+	}
+
+	actual := status.StatusList[0]
+	if actual.Result != healthchecks.OK || !strings.HasPrefix(actual.Details, "Certificate: subject=O=Acme Co issuer=O=Acme Co ") {
+		t.Errorf("Status should be `OK` with the certificate, was `%v`", actual)
+	}
+}
+
+func TestHttpStatusChecker_CheckStatusCertificateExpiring(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", server.URL+"/status/aggregate",
+		httpmock.NewStringResponder(200, `["WARN",{"description":"Redis","result":"WARN","details":"Slow"}]`))
+
+	// The test server certificate expires in less than 100 years
+	httpStatusChecker := HttpStatusChecker{BaseUrl: server.URL, Certificate: &tlssc.CertificateStatusChecker{CriticalDays: 365 * 100}}
+	actual := httpStatusChecker.CheckStatus("Service name").StatusList[0]
+
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+	if !strings.HasPrefix(actual.Details, "Service name check failed: WARN - ") || !strings.Contains(actual.Details, "; Certificate: Certificate expires in ") {
+		t.Errorf("Details should hold the aggregate and certificate details, was `%s`", actual.Details)
+	}
+}
+
+func TestHttpStatusChecker_CheckStatusCertificateNotHttps(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://something.com/status/aggregate",
+		httpmock.NewStringResponder(200, `["OK"]`))
+
+	httpStatusChecker := HttpStatusChecker{BaseUrl: "http://something.com", Certificate: &tlssc.CertificateStatusChecker{}}
+	status := httpStatusChecker.CheckStatus("Service name")
+
+	expected := healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: "Service name check OK",
+				Result:      healthchecks.CRITICAL,
+				Details:     "Certificate: No certificate to check, http://something.com is not an https url",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(status.StatusList, expected.StatusList) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, status)
+	}
+}
+
 func TestHttpStatusChecker_Traverse(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
@@ -0,0 +1,205 @@
+package hystrixsc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/afex/hystrix-go/hystrix"
+	"github.com/afex/hystrix-go/hystrix/metric_collector"
+	"github.com/afex/hystrix-go/hystrix/rolling"
+	"github.com/hootsuite/healthchecks"
+)
+
+// DefaultWarningErrorRatio is the ratio of a circuit's ErrorPercentThreshold from which a warning is raised
+const DefaultWarningErrorRatio = 0.8
+
+var (
+	registerCollectorOnce sync.Once
+	collectorsMu          sync.RWMutex
+	collectors            = map[string]*circuitMetrics{}
+)
+
+// RegisterMetricCollector registers the hystrix metric collector the HystrixCircuitsStatusChecker reads error
+// percentages and concurrency from. It must be called before the hystrix commands first run, as hystrix only
+// attaches collectors to new circuits. Calling it more than once has no effect.
+func RegisterMetricCollector() {
+	registerCollectorOnce.Do(func() {
+		metricCollector.Registry.Register(newCircuitMetrics)
+	})
+}
+
+// Rolling metrics of a circuit, over the same 10 seconds window hystrix uses to trip circuits
+type circuitMetrics struct {
+	mu          sync.RWMutex
+	requests    *rolling.Number
+	errors      *rolling.Number
+	concurrency *rolling.Number
+}
+
+func newCircuitMetrics(name string) metricCollector.MetricCollector {
+	m := &circuitMetrics{}
+	m.Reset()
+
+	collectorsMu.Lock()
+	collectors[name] = m
+	collectorsMu.Unlock()
+
+	return m
+}
+
+func (m *circuitMetrics) Update(r metricCollector.MetricResult) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	m.requests.Increment(r.Attempts)
+	m.errors.Increment(r.Errors)
+	m.concurrency.UpdateMax(r.ConcurrencyInUse)
+}
+
+func (m *circuitMetrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests = rolling.NewNumber()
+	m.errors = rolling.NewNumber()
+	m.concurrency = rolling.NewNumber()
+}
+
+// Get the number of requests, error percentage and maximum concurrency in use ratio of the window
+func (m *circuitMetrics) snapshot(now time.Time) (float64, int, float64) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	requests := m.requests.Sum(now)
+	errorPercent := 0
+	if requests > 0 {
+		// Rounded the same way hystrix does
+		errorPercent = int(m.errors.Sum(now)/requests*100 + 0.5)
+	}
+
+	return requests, errorPercent, m.concurrency.Max(now)
+}
+
+// HystrixCircuitsStatusChecker checks the hystrix circuits of CommandNames, or of every configured hystrix command if
+// empty.
+//
+// The returned StatusList starts with the overall status followed by one entry per circuit with its state, error
+// percentage and concurrency saturation. An open circuit is CRITICAL. A closed circuit is WARN once its error
+// percentage reaches WarningErrorRatio of its ErrorPercentThreshold, or its concurrency in use reaches
+// WarningConcurrencyRatio of its MaxConcurrentRequests. The error percentage is only evaluated once the circuit
+// reached its RequestVolumeThreshold, as hystrix does. Metrics are only available if RegisterMetricCollector was
+// called before the circuits were created.
+type HystrixCircuitsStatusChecker struct {
+	CommandNames            []string // Optional hystrix commands to check, leave empty to check every configured command
+	WarningErrorRatio       float64  // Optional ratio of the ErrorPercentThreshold to warn from, defaults to DefaultWarningErrorRatio
+	WarningConcurrencyRatio float64  // Optional ratio of MaxConcurrentRequests in use to warn from, disabled when 0
+}
+
+func (h HystrixCircuitsStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	s := healthchecks.Status{
+		Description: name,
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	settings := hystrix.GetCircuitSettings()
+	commandNames := h.CommandNames
+	if len(commandNames) == 0 {
+		for commandName := range settings {
+			commandNames = append(commandNames, commandName)
+		}
+		sort.Strings(commandNames)
+	}
+
+	circuitStatuses := make([]healthchecks.Status, 0, len(commandNames))
+	open, warning := 0, 0
+	for _, commandName := range commandNames {
+		circuitStatus := h.checkCircuit(commandName, settings[commandName])
+		switch circuitStatus.Result {
+		case healthchecks.CRITICAL:
+			open++
+		case healthchecks.WARNING:
+			warning++
+		}
+		circuitStatuses = append(circuitStatuses, circuitStatus)
+	}
+
+	reasons := []string{}
+	if open > 0 {
+		s.Result = healthchecks.CRITICAL
+		reasons = append(reasons, fmt.Sprintf("%d circuits are open", open))
+	}
+	if warning > 0 {
+		s.Result = healthchecks.Worst(s.Result, healthchecks.WARNING)
+		reasons = append(reasons, fmt.Sprintf("%d circuits are nearing their thresholds", warning))
+	}
+	reasons = append(reasons, fmt.Sprintf("%d circuits", len(commandNames)))
+	s.Details = strings.Join(reasons, "; ")
+
+	return healthchecks.StatusList{StatusList: append([]healthchecks.Status{s}, circuitStatuses...)}
+}
+
+func (h HystrixCircuitsStatusChecker) checkCircuit(commandName string, settings *hystrix.Settings) healthchecks.Status {
+	s := healthchecks.Status{
+		Description: commandName,
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	c, _, err := hystrix.GetCircuit(commandName)
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = err.Error()
+		return s
+	}
+
+	state := "closed"
+	if c.IsOpen() {
+		state = "open"
+	}
+	details := []string{fmt.Sprintf("state=%s", state)}
+
+	collectorsMu.RLock()
+	metrics, ok := collectors[commandName]
+	collectorsMu.RUnlock()
+
+	reasons := []string{}
+	if state == "open" {
+		s.Result = healthchecks.CRITICAL
+		reasons = append(reasons, "Circuit breaker is OPEN")
+	}
+
+	if ok && settings != nil {
+		requests, errorPercent, concurrency := metrics.snapshot(time.Now())
+		details = append(details,
+			fmt.Sprintf("error_percent=%d%% (threshold %d%%)", errorPercent, settings.ErrorPercentThreshold),
+			fmt.Sprintf("requests=%.0f", requests),
+			fmt.Sprintf("concurrency=%.0f%% (max %d)", concurrency*100, settings.MaxConcurrentRequests),
+		)
+
+		// Only warn about a closed circuit
+		if state == "closed" {
+			warningErrorRatio := h.WarningErrorRatio
+			if warningErrorRatio == 0 {
+				warningErrorRatio = DefaultWarningErrorRatio
+			}
+			warningErrorPercent := warningErrorRatio * float64(settings.ErrorPercentThreshold)
+			if uint64(requests) >= settings.RequestVolumeThreshold && float64(errorPercent) >= warningErrorPercent {
+				s.Result = healthchecks.WARNING
+				reasons = append(reasons, fmt.Sprintf("Error percentage is nearing threshold of %d%%", settings.ErrorPercentThreshold))
+			}
+
+			if h.WarningConcurrencyRatio > 0 && concurrency >= h.WarningConcurrencyRatio {
+				s.Result = healthchecks.WARNING
+				reasons = append(reasons, fmt.Sprintf("Concurrency is nearing maximum of %d", settings.MaxConcurrentRequests))
+			}
+		}
+	}
+
+	s.Details = strings.Join(append(reasons, strings.Join(details, " ")), "; ")
+
+	return s
+}
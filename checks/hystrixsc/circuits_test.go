@@ -0,0 +1,143 @@
+package hystrixsc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/afex/hystrix-go/hystrix"
+	"github.com/hootsuite/healthchecks"
+)
+
+func init() {
+	// Registered before any circuit is created
+	RegisterMetricCollector()
+
+	for _, commandName := range []string{"circuits-ok", "circuits-warn", "circuits-open"} {
+		hystrix.ConfigureCommand(commandName, hystrix.CommandConfig{
+			Timeout:                5000,
+			MaxConcurrentRequests:  10,
+			ErrorPercentThreshold:  50,
+			RequestVolumeThreshold: 5,
+			SleepWindow:            60000,
+		})
+	}
+}
+
+func TestCircuits(t *testing.T) {
+	for i := 0; i < 5; i++ {
+		runCommand("circuits-ok", nil)
+	}
+
+	for i := 0; i < 6; i++ {
+		runCommand("circuits-warn", nil)
+	}
+	for i := 0; i < 4; i++ {
+		runCommand("circuits-warn", errors.New("An error"))
+	}
+
+	for i := 0; i < 5; i++ {
+		runCommand("circuits-open", errors.New("An error"))
+	}
+
+	statusChecker := HystrixCircuitsStatusChecker{CommandNames: []string{"circuits-ok", "circuits-warn", "circuits-open"}}
+	s := statusChecker.CheckStatus("The circuits")
+
+	if len(s.StatusList) != 4 {
+		t.Fatalf("Length of StatusList should be 4, was %d", len(s.StatusList))
+	}
+
+	overall := s.StatusList[0]
+	if overall.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", overall.Result)
+	}
+	eDetails := "1 circuits are open; 1 circuits are nearing their thresholds; 3 circuits"
+	if overall.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, overall.Details)
+	}
+
+	expected := []struct {
+		description string
+		result      healthchecks.AlertLevel
+		details     string
+	}{
+		{"circuits-ok", healthchecks.OK, "state=closed error_percent=0% (threshold 50%) requests=5 "},
+		{"circuits-warn", healthchecks.WARNING, "Error percentage is nearing threshold of 50%; state=closed error_percent=40% (threshold 50%) requests=10 "},
+		{"circuits-open", healthchecks.CRITICAL, "Circuit breaker is OPEN; state=open error_percent=100% (threshold 50%) requests=5 "},
+	}
+	for i, e := range expected {
+		actual := s.StatusList[i+1]
+		if actual.Description != e.description || actual.Result != e.result || !strings.HasPrefix(actual.Details, e.details) {
+			t.Errorf("Circuit status should be `%s` `%s` starting with `%s`, was `%v`", e.description, e.result, e.details, actual)
+		}
+	}
+}
+
+func TestCircuitsDiscovery(t *testing.T) {
+	hystrix.ConfigureCommand("circuits-discovered", hystrix.CommandConfig{})
+
+	s := HystrixCircuitsStatusChecker{}.CheckStatus("The circuits")
+
+	found := false
+	for _, actual := range s.StatusList[1:] {
+		if actual.Description == "circuits-discovered" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("StatusList should contain the configured command `circuits-discovered`, was `%v`", s.StatusList)
+	}
+}
+
+func TestCircuitsConcurrency(t *testing.T) {
+	hystrix.ConfigureCommand("circuits-busy", hystrix.CommandConfig{MaxConcurrentRequests: 2})
+
+	// Keep a command running while another one completes
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	hystrix.Go("circuits-busy", func() error {
+		<-release
+		close(finished)
+		return nil
+	}, nil)
+	runCommand("circuits-busy", nil)
+	close(release)
+	<-finished
+
+	statusChecker := HystrixCircuitsStatusChecker{CommandNames: []string{"circuits-busy"}, WarningConcurrencyRatio: 0.5}
+	actual := statusChecker.CheckStatus("The circuits").StatusList[1]
+
+	if actual.Result != healthchecks.WARNING {
+		t.Errorf("Result should be `WARNING`, was `%s`", actual.Result)
+	}
+
+	eDetails := "Concurrency is nearing maximum of 2; state=closed"
+	if !strings.HasPrefix(actual.Details, eDetails) {
+		t.Errorf("Details should start with `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func runCommand(commandName string, err error) (string, error) {
+	resultChan := make(chan string, 1)
+	errChan := hystrix.Go(commandName, func() error {
+		if err != nil {
+			return err
+		}
+
+		resultChan <- "done"
+		return nil
+	}, nil)
+
+	// Wait for the circuit breaker to record the result
+	wait := time.Millisecond * 50
+
+	select {
+	case result := <-resultChan:
+		time.Sleep(wait)
+		return result, nil
+	case err := <-errChan:
+		time.Sleep(wait)
+		return "", err
+	}
+}
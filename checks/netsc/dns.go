@@ -0,0 +1,182 @@
+package netsc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// DNS record types supported by the DNSStatusChecker
+const (
+	RecordA     = "A"
+	RecordAAAA  = "AAAA"
+	RecordCNAME = "CNAME"
+	RecordMX    = "MX"
+	RecordNS    = "NS"
+	RecordTXT   = "TXT"
+)
+
+// A thin DNS resolver wrapper used for mocks / tests, implemented by *net.Resolver
+type Resolver interface {
+	LookupIP(ctx context.Context, network string, host string) ([]net.IP, error)
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupNS(ctx context.Context, name string) ([]*net.NS, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// DNSStatusChecker checks that a name resolves to the expected records.
+//
+// The name is resolved through Resolver if set, else through the DNS server at Server if set, else through the system
+// resolver. A failed lookup, fewer answers than MinAnswers or a missing expected answer results in a CRITICAL status.
+// Latency thresholds apply to the time taken to resolve the name.
+type DNSStatusChecker struct {
+	Host            string        // The name to resolve
+	RecordType      string        // Optional record type to resolve, one of the Record constants, defaults to RecordA
+	Resolver        Resolver      // Optional resolver to use
+	Server          string        // Optional host:port of the DNS server to use if Resolver is nil
+	Timeout         time.Duration // Optional timeout of the lookup, defaults to DefaultTimeout
+	MinAnswers      int           // Optional minimum number of answers, defaults to 1
+	ExpectedAnswers []string      // Optional answers that must all be resolved, such as addresses or host names
+	WarningLatency  time.Duration // Optional latency above which a warning is raised
+	CriticalLatency time.Duration // Optional latency above which a critical alert is raised
+}
+
+func (d DNSStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	timeout := d.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	recordType := strings.ToUpper(d.RecordType)
+	if recordType == "" {
+		recordType = RecordA
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	answers, err := lookup(ctx, d.resolver(), recordType, d.Host)
+	latency := time.Since(start)
+	if err != nil {
+		return netStatus(name, healthchecks.CRITICAL, err.Error())
+	}
+
+	failures := []string{}
+
+	minAnswers := d.MinAnswers
+	if minAnswers <= 0 {
+		minAnswers = 1
+	}
+	if len(answers) < minAnswers {
+		failures = append(failures, fmt.Sprintf("Resolved %d %s records, expecting at least %d", len(answers), recordType, minAnswers))
+	}
+
+	for _, expected := range d.ExpectedAnswers {
+		if !containsAnswer(answers, expected) {
+			failures = append(failures, fmt.Sprintf("Expected %s record `%s` not found", recordType, expected))
+		}
+	}
+
+	details := fmt.Sprintf("Resolved %s %s to [%s] in %s", d.Host, recordType, strings.Join(answers, ", "), latency)
+
+	if len(failures) > 0 {
+		return netStatus(name, healthchecks.CRITICAL, fmt.Sprintf("%s; %s", strings.Join(failures, "; "), details))
+	}
+
+	return latencyStatus(name, latency, d.WarningLatency, d.CriticalLatency, details)
+}
+
+func (d DNSStatusChecker) resolver() Resolver {
+	if d.Resolver != nil {
+		return d.Resolver
+	}
+
+	if d.Server != "" {
+		server := d.Server
+		return &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network string, address string) (net.Conn, error) {
+				dialer := net.Dialer{}
+				return dialer.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	return net.DefaultResolver
+}
+
+// Resolve the records of a type, formatted as strings
+func lookup(ctx context.Context, resolver Resolver, recordType string, host string) ([]string, error) {
+	answers := []string{}
+
+	switch recordType {
+	case RecordA, RecordAAAA:
+		network := "ip4"
+		if recordType == RecordAAAA {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case RecordCNAME:
+		cname, err := resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, cname)
+	case RecordMX:
+		mxs, err := resolver.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range mxs {
+			answers = append(answers, mx.Host)
+		}
+	case RecordNS:
+		nss, err := resolver.LookupNS(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range nss {
+			answers = append(answers, ns.Host)
+		}
+	case RecordTXT:
+		txts, err := resolver.LookupTXT(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, txts...)
+	default:
+		return nil, fmt.Errorf("Unsupported record type `%s`", recordType)
+	}
+
+	return answers, nil
+}
+
+// Compare addresses by value and host names case-insensitively, ignoring the trailing dot
+func containsAnswer(answers []string, expected string) bool {
+	expectedIP := net.ParseIP(expected)
+	for _, answer := range answers {
+		if expectedIP != nil {
+			if ip := net.ParseIP(answer); ip != nil && ip.Equal(expectedIP) {
+				return true
+			}
+			continue
+		}
+
+		if strings.EqualFold(strings.TrimSuffix(answer, "."), strings.TrimSuffix(expected, ".")) {
+			return true
+		}
+	}
+	return false
+}
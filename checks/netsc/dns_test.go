@@ -0,0 +1,125 @@
+package netsc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+func TestDNSOK(t *testing.T) {
+	dnsStatusChecker := DNSStatusChecker{
+		Host:            "db.example.com",
+		Resolver:        MockResolver{},
+		MinAnswers:      2,
+		ExpectedAnswers: []string{"10.0.0.2"},
+	}
+
+	s := dnsStatusChecker.CheckStatus("The database DNS")
+
+	if len(s.StatusList) != 1 {
+		t.Fatalf("Length of StatusList should be 1, was %d", len(s.StatusList))
+	}
+
+	actual := s.StatusList[0]
+	if actual.Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was `%s`", actual.Result)
+	}
+
+	if !strings.HasPrefix(actual.Details, "Resolved db.example.com A to [10.0.0.1, 10.0.0.2] in ") {
+		t.Errorf("Details should report the answers, was `%s`", actual.Details)
+	}
+}
+
+func TestDNSAssertionsFailed(t *testing.T) {
+	dnsStatusChecker := DNSStatusChecker{
+		Host:            "example.com",
+		RecordType:      "mx",
+		Resolver:        MockResolver{},
+		MinAnswers:      3,
+		ExpectedAnswers: []string{"mx1.example.com", "MX2.EXAMPLE.COM", "mx3.example.com"},
+	}
+
+	actual := dnsStatusChecker.CheckStatus("The mail DNS").StatusList[0]
+
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+
+	eDetails := "Resolved 2 MX records, expecting at least 3; Expected MX record `mx3.example.com` not found; " +
+		"Resolved example.com MX to [mx1.example.com., mx2.example.com.] in "
+	if !strings.HasPrefix(actual.Details, eDetails) {
+		t.Errorf("Details should start with `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestDNSLookupError(t *testing.T) {
+	dnsStatusChecker := DNSStatusChecker{
+		Host:       "missing.example.com",
+		RecordType: RecordAAAA,
+		Resolver:   MockResolver{},
+	}
+
+	actual := dnsStatusChecker.CheckStatus("The DNS").StatusList[0]
+
+	eDetails := "lookup missing.example.com: no such host"
+	if actual.Result != healthchecks.CRITICAL || actual.Details != eDetails {
+		t.Errorf("Status should be `CRITICAL` with `%s`, was `%s` with `%s`", eDetails, actual.Result, actual.Details)
+	}
+}
+
+func TestDNSUnsupportedRecordType(t *testing.T) {
+	actual := DNSStatusChecker{Host: "example.com", RecordType: "SOA", Resolver: MockResolver{}}.CheckStatus("The DNS").StatusList[0]
+
+	eDetails := "Unsupported record type `SOA`"
+	if actual.Result != healthchecks.CRITICAL || actual.Details != eDetails {
+		t.Errorf("Status should be `CRITICAL` with `%s`, was `%s` with `%s`", eDetails, actual.Result, actual.Details)
+	}
+}
+
+func TestDNSLatency(t *testing.T) {
+	dnsStatusChecker := DNSStatusChecker{
+		Host:           "db.example.com",
+		Resolver:       MockResolver{delay: 10 * time.Millisecond},
+		WarningLatency: time.Millisecond,
+	}
+
+	actual := dnsStatusChecker.CheckStatus("The DNS").StatusList[0]
+
+	if actual.Result != healthchecks.WARNING || !strings.HasPrefix(actual.Details, "Latency of ") {
+		t.Errorf("Status should be `WARNING` with the latency, was `%s` with `%s`", actual.Result, actual.Details)
+	}
+}
+
+// Mocks
+type MockResolver struct {
+	delay time.Duration
+}
+
+func (r MockResolver) LookupIP(ctx context.Context, network string, host string) ([]net.IP, error) {
+	time.Sleep(r.delay)
+	if host == "db.example.com" && network == "ip4" {
+		return []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func (r MockResolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (r MockResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	return []*net.MX{{Host: "mx1.example.com.", Pref: 10}, {Host: "mx2.example.com.", Pref: 20}}, nil
+}
+
+func (r MockResolver) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r MockResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return nil, errors.New("not implemented")
+}
@@ -0,0 +1,135 @@
+package netsc
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// DefaultTimeout is the timeout of a check when none is configured
+const DefaultTimeout = 5 * time.Second
+
+// TCPStatusChecker checks that a TCP service accepts connections, optionally performing a TLS handshake and matching
+// the banner the service sends on connection, as SMTP, FTP or SSH servers do.
+//
+// A failed connection, handshake or banner match results in a CRITICAL status. Latency thresholds apply to the time
+// taken to connect, including the handshake and banner read.
+type TCPStatusChecker struct {
+	Address         string        // The host:port to connect to
+	Timeout         time.Duration // Optional timeout of the whole check, defaults to DefaultTimeout
+	TLSConfig       *tls.Config   // Optional TLS configuration, a TLS handshake is performed when set
+	BannerRegex     string        // Optional regular expression the first line sent by the service must match
+	WarningLatency  time.Duration // Optional latency above which a warning is raised
+	CriticalLatency time.Duration // Optional latency above which a critical alert is raised
+}
+
+func (t TCPStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	timeout := t.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	var bannerRegex *regexp.Regexp
+	if t.BannerRegex != "" {
+		var err error
+		bannerRegex, err = regexp.Compile(t.BannerRegex)
+		if err != nil {
+			return netStatus(name, healthchecks.CRITICAL, fmt.Sprintf("Invalid banner regex `%s`: %s", t.BannerRegex, err.Error()))
+		}
+	}
+
+	start := time.Now()
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn net.Conn
+	var err error
+	if t.TLSConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", t.Address, t.tlsConfig())
+	} else {
+		conn, err = dialer.Dial("tcp", t.Address)
+	}
+	if err != nil {
+		return netStatus(name, healthchecks.CRITICAL, err.Error())
+	}
+	defer conn.Close()
+
+	details := []string{}
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		details = append(details, fmt.Sprintf("TLS version %s", tlsVersionName(tlsConn.ConnectionState().Version)))
+	}
+
+	if bannerRegex != nil {
+		conn.SetReadDeadline(start.Add(timeout))
+		banner, err := bufio.NewReader(conn).ReadString('\n')
+		banner = strings.TrimRight(banner, "\r\n")
+		if err != nil && banner == "" {
+			return netStatus(name, healthchecks.CRITICAL, fmt.Sprintf("Error reading banner: %s", err.Error()))
+		}
+		if !bannerRegex.MatchString(banner) {
+			return netStatus(name, healthchecks.CRITICAL, fmt.Sprintf("Banner `%s` does not match `%s`", banner, t.BannerRegex))
+		}
+		details = append(details, fmt.Sprintf("banner `%s`", banner))
+	}
+
+	latency := time.Since(start)
+	details = append([]string{fmt.Sprintf("Connected to %s in %s", t.Address, latency)}, details...)
+
+	return latencyStatus(name, latency, t.WarningLatency, t.CriticalLatency, strings.Join(details, ", "))
+}
+
+// Use the host of the address as server name unless the configuration sets one
+func (t TCPStatusChecker) tlsConfig() *tls.Config {
+	config := t.TLSConfig.Clone()
+	if config.ServerName == "" {
+		if host, _, err := net.SplitHostPort(t.Address); err == nil {
+			config.ServerName = host
+		}
+	}
+	return config
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "1.0"
+	case tls.VersionTLS11:
+		return "1.1"
+	case tls.VersionTLS12:
+		return "1.2"
+	case tls.VersionTLS13:
+		return "1.3"
+	default:
+		return fmt.Sprintf("0x%04x", version)
+	}
+}
+
+// Build the status of a successful check, raising an alert if the latency exceeds a threshold
+func latencyStatus(name string, latency time.Duration, warningLatency time.Duration, criticalLatency time.Duration, details string) healthchecks.StatusList {
+	if criticalLatency > 0 && latency > criticalLatency {
+		return netStatus(name, healthchecks.CRITICAL, fmt.Sprintf("Latency of %s exceeds threshold of %s; %s", latency, criticalLatency, details))
+	}
+
+	if warningLatency > 0 && latency > warningLatency {
+		return netStatus(name, healthchecks.WARNING, fmt.Sprintf("Latency of %s exceeds threshold of %s; %s", latency, warningLatency, details))
+	}
+
+	return netStatus(name, healthchecks.OK, details)
+}
+
+func netStatus(name string, result healthchecks.AlertLevel, details string) healthchecks.StatusList {
+	return healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: name,
+				Result:      result,
+				Details:     details,
+			},
+		},
+	}
+}
@@ -0,0 +1,136 @@
+package netsc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+func TestTCPOK(t *testing.T) {
+	address := listenWithBanner(t, "220 smtp.example.com ESMTP ready\r\n")
+
+	s := TCPStatusChecker{Address: address, BannerRegex: `^220 `}.CheckStatus("The relay")
+
+	if len(s.StatusList) != 1 {
+		t.Fatalf("Length of StatusList should be 1, was %d", len(s.StatusList))
+	}
+
+	actual := s.StatusList[0]
+	if actual.Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was `%s`", actual.Result)
+	}
+
+	if !strings.HasPrefix(actual.Details, "Connected to "+address+" in ") || !strings.HasSuffix(actual.Details, ", banner `220 smtp.example.com ESMTP ready`") {
+		t.Errorf("Details should report the connection and banner, was `%s`", actual.Details)
+	}
+}
+
+func TestTCPBannerMismatch(t *testing.T) {
+	address := listenWithBanner(t, "554 No SMTP service here\r\n")
+
+	actual := TCPStatusChecker{Address: address, BannerRegex: `^220 `}.CheckStatus("The relay").StatusList[0]
+
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+
+	eDetails := "Banner `554 No SMTP service here` does not match `^220 `"
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestTCPBannerTimeout(t *testing.T) {
+	address := listenWithBanner(t, "")
+
+	actual := TCPStatusChecker{Address: address, BannerRegex: `^220 `, Timeout: 50 * time.Millisecond}.CheckStatus("The relay").StatusList[0]
+
+	if actual.Result != healthchecks.CRITICAL || !strings.HasPrefix(actual.Details, "Error reading banner: ") {
+		t.Errorf("Status should be `CRITICAL` with a banner error, was `%s` with `%s`", actual.Result, actual.Details)
+	}
+}
+
+func TestTCPConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	actual := TCPStatusChecker{Address: address}.CheckStatus("The relay").StatusList[0]
+
+	if actual.Result != healthchecks.CRITICAL || !strings.Contains(actual.Details, "connection refused") {
+		t.Errorf("Status should be `CRITICAL` with connection refused, was `%s` with `%s`", actual.Result, actual.Details)
+	}
+}
+
+func TestTCPTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	address := server.Listener.Addr().String()
+	actual := TCPStatusChecker{Address: address, TLSConfig: &tls.Config{RootCAs: roots}}.CheckStatus("The service").StatusList[0]
+
+	if actual.Result != healthchecks.OK || !strings.HasSuffix(actual.Details, ", TLS version 1.3") {
+		t.Errorf("Status should be `OK` with the TLS version, was `%s` with `%s`", actual.Result, actual.Details)
+	}
+
+	// The certificate is not trusted by the system roots
+	actual = TCPStatusChecker{Address: address, TLSConfig: &tls.Config{}}.CheckStatus("The service").StatusList[0]
+
+	if actual.Result != healthchecks.CRITICAL || !strings.Contains(actual.Details, "certificate") {
+		t.Errorf("Status should be `CRITICAL` with a certificate error, was `%s` with `%s`", actual.Result, actual.Details)
+	}
+}
+
+func TestTCPLatency(t *testing.T) {
+	address := listenWithBanner(t, "")
+
+	actual := TCPStatusChecker{Address: address, WarningLatency: time.Nanosecond}.CheckStatus("The relay").StatusList[0]
+
+	if actual.Result != healthchecks.WARNING || !strings.HasPrefix(actual.Details, "Latency of ") {
+		t.Errorf("Status should be `WARNING` with the latency, was `%s` with `%s`", actual.Result, actual.Details)
+	}
+
+	actual = TCPStatusChecker{Address: address, WarningLatency: time.Nanosecond, CriticalLatency: time.Nanosecond}.CheckStatus("The relay").StatusList[0]
+
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+}
+
+// Listen on a local port, sending the banner to every connection
+func listenWithBanner(t *testing.T, banner string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte(banner))
+			go func() {
+				time.Sleep(time.Second)
+				conn.Close()
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
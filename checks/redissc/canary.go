@@ -0,0 +1,94 @@
+package redissc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+	"github.com/hootsuite/healthchecks/internal/canary"
+)
+
+const (
+	// Default prefix of the canary keys
+	DefaultCanaryKeyPrefix = "healthchecks:canary:"
+	// Default expiration of the canary keys, so keys left over by a failed delete do not pile up
+	DefaultCanaryTTL = time.Minute
+)
+
+// A thin Redis wrapper for the commands used by the canary check
+type RedisCanaryClient interface {
+	Set(key string, value string, expiration time.Duration) error
+	Get(key string) (string, error)
+	Del(key string) error
+}
+
+// RedisCanaryStatusChecker writes a uniquely keyed value, reads it back, verifies it and deletes it again. Unlike
+// `PING` this catches a Redis that is up but rejecting writes, e.g. when it is out of memory or a read-only replica.
+//
+// The latency of each step is reported in the details. Any failed step results in a CRITICAL status and a round trip
+// slower than WarningLatency in a warning.
+type RedisCanaryStatusChecker struct {
+	Client         RedisCanaryClient
+	KeyPrefix      string        // Optional prefix of the canary keys, defaults to `healthchecks:canary:`
+	TTL            time.Duration // Optional expiration of the canary keys, defaults to 1 minute
+	WarningLatency time.Duration // Optional round trip latency above which a warning is raised
+	// Optional generator of the canary key suffix and value, defaults to a random key suffix and value
+	Values func() (string, string, error)
+}
+
+func (r RedisCanaryStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	keyPrefix := r.KeyPrefix
+	if keyPrefix == "" {
+		keyPrefix = DefaultCanaryKeyPrefix
+	}
+
+	ttl := r.TTL
+	if ttl <= 0 {
+		ttl = DefaultCanaryTTL
+	}
+
+	s := healthchecks.Status{
+		Description: name,
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	values := r.Values
+	if values == nil {
+		values = canary.Values
+	}
+
+	key, value, err := values()
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = err.Error()
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+	key = keyPrefix + key
+
+	total, details, err := canary.RoundTrip(
+		value,
+		func() error {
+			return r.Client.Set(key, value, ttl)
+		},
+		func() (string, error) {
+			return r.Client.Get(key)
+		},
+		func() error {
+			return r.Client.Del(key)
+		},
+	)
+
+	switch {
+	case err != nil:
+		s.Result = healthchecks.CRITICAL
+		s.Details = fmt.Sprintf("%v; %s", err, details)
+	case r.WarningLatency > 0 && total > r.WarningLatency:
+		s.Result = healthchecks.WARNING
+		s.Details = fmt.Sprintf("Round trip of %s exceeds threshold of %s; %s", total, r.WarningLatency, details)
+	default:
+		s.Details = details
+	}
+
+	return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+}
@@ -0,0 +1,131 @@
+package redissc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/hootsuite/healthchecks"
+)
+
+func TestCanaryOK(t *testing.T) {
+	client := &MapRedis{values: map[string]string{}}
+	redisCanaryStatusChecker := RedisCanaryStatusChecker{Client: client, WarningLatency: time.Minute}
+
+	s := redisCanaryStatusChecker.CheckStatus("the redis")
+
+	if len(s.StatusList) != 1 {
+		t.Errorf("Length of StatusList should be 1, was %d", len(s.StatusList))
+	}
+
+	actual := s.StatusList[0]
+	if actual.Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was `%s` (%s)", actual.Result, actual.Details)
+	}
+
+	if !strings.HasPrefix(actual.Details, "write=") || !strings.Contains(actual.Details, " read=") || !strings.Contains(actual.Details, " delete=") {
+		t.Errorf("Details should contain the latency of each step, was `%s`", actual.Details)
+	}
+
+	if len(client.values) != 0 {
+		t.Errorf("Canary key should have been deleted, found %v", client.values)
+	}
+
+	if !strings.HasPrefix(client.lastKey, DefaultCanaryKeyPrefix) || client.lastTTL != DefaultCanaryTTL {
+		t.Errorf("Canary key should use the default prefix and TTL, was `%s` with %s", client.lastKey, client.lastTTL)
+	}
+}
+
+func TestCanaryValues(t *testing.T) {
+	client := &MapRedis{values: map[string]string{}, getOverride: "something-else"}
+	redisCanaryStatusChecker := RedisCanaryStatusChecker{
+		Client: client,
+		Values: func() (string, string, error) { return "key", "value", nil },
+	}
+
+	actual := redisCanaryStatusChecker.CheckStatus("the redis").StatusList[0]
+	if !strings.HasPrefix(actual.Details, "read failed: expected value `value`, got `something-else`") {
+		t.Errorf("Details should report the value mismatch, was `%s`", actual.Details)
+	}
+
+	if client.lastKey != DefaultCanaryKeyPrefix+"key" {
+		t.Errorf("Canary key should be `%skey`, was `%s`", DefaultCanaryKeyPrefix, client.lastKey)
+	}
+}
+
+func TestCanaryWriteRejected(t *testing.T) {
+	client := &MapRedis{values: map[string]string{}, setErr: errors.New("OOM command not allowed when used memory > 'maxmemory'")}
+	redisCanaryStatusChecker := RedisCanaryStatusChecker{Client: client}
+
+	actual := redisCanaryStatusChecker.CheckStatus("the redis").StatusList[0]
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+
+	eDetails := "write failed: OOM command not allowed when used memory > 'maxmemory'; write="
+	if !strings.HasPrefix(actual.Details, eDetails) {
+		t.Errorf("Details should start with `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestCanaryValueMismatch(t *testing.T) {
+	client := &MapRedis{values: map[string]string{}, getOverride: "something-else"}
+	redisCanaryStatusChecker := RedisCanaryStatusChecker{Client: client, KeyPrefix: "myservice:"}
+
+	actual := redisCanaryStatusChecker.CheckStatus("the redis").StatusList[0]
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+
+	if !strings.Contains(actual.Details, "read failed: expected value") {
+		t.Errorf("Details should report the value mismatch, was `%s`", actual.Details)
+	}
+
+	if len(client.values) != 0 || !strings.HasPrefix(client.lastKey, "myservice:") {
+		t.Errorf("Canary key `%s` should have been deleted, found %v", client.lastKey, client.values)
+	}
+}
+
+func TestCanarySlowRoundTrip(t *testing.T) {
+	client := &MapRedis{values: map[string]string{}, delay: 5 * time.Millisecond}
+	redisCanaryStatusChecker := RedisCanaryStatusChecker{Client: client, WarningLatency: time.Millisecond}
+
+	actual := redisCanaryStatusChecker.CheckStatus("the redis").StatusList[0]
+	if actual.Result != healthchecks.WARNING {
+		t.Errorf("Result should be `WARNING`, was `%s`", actual.Result)
+	}
+}
+
+// Mocks
+type MapRedis struct {
+	values      map[string]string
+	setErr      error
+	getOverride string
+	delay       time.Duration
+	lastKey     string
+	lastTTL     time.Duration
+}
+
+func (r *MapRedis) Set(key string, value string, expiration time.Duration) error {
+	time.Sleep(r.delay)
+	if r.setErr != nil {
+		return r.setErr
+	}
+	r.values[key] = value
+	r.lastKey = key
+	r.lastTTL = expiration
+	return nil
+}
+
+func (r *MapRedis) Get(key string) (string, error) {
+	if r.getOverride != "" {
+		return r.getOverride, nil
+	}
+	return r.values[key], nil
+}
+
+func (r *MapRedis) Del(key string) error {
+	delete(r.values, key)
+	return nil
+}
@@ -0,0 +1,152 @@
+package redissc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// Number of hash slots of a Redis Cluster
+const clusterSlots = 16384
+
+// A thin Redis Cluster wrapper used for mocks / tests
+type RedisClusterClient interface {
+	// Returns the raw output of `CLUSTER INFO`
+	ClusterInfo() (string, error)
+	// Returns the raw output of `CLUSTER NODES`
+	ClusterNodes() (string, error)
+}
+
+// RedisClusterStatusChecker checks the state of a Redis Cluster and each of its nodes.
+//
+// The returned StatusList starts with the status of the cluster followed by one entry per node. The cluster is
+// CRITICAL if its state is `fail`, slots are unassigned or failing, or a master failed, and WARN if a replica failed or
+// a node is suspected to fail.
+type RedisClusterStatusChecker struct {
+	Client RedisClusterClient
+}
+
+type clusterNode struct {
+	address   string
+	flags     map[string]bool
+	linkState string
+	slots     []string
+}
+
+func (n clusterNode) role() string {
+	if n.flags["master"] {
+		return "master"
+	}
+	return "replica"
+}
+
+func (r RedisClusterStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	s := healthchecks.Status{
+		Description: name,
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	rawInfo, err := r.Client.ClusterInfo()
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = err.Error()
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	rawNodes, err := r.Client.ClusterNodes()
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = err.Error()
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	info := ParseInfo(rawInfo)
+	reasons := []string{}
+	raise := func(level healthchecks.AlertLevel, reason string) {
+		s.Result = healthchecks.Worst(s.Result, level)
+		reasons = append(reasons, reason)
+	}
+
+	if state := info["cluster_state"]; state != "ok" {
+		raise(healthchecks.CRITICAL, fmt.Sprintf("Cluster state is `%s`", state))
+	}
+
+	if assigned := infoInt(info, "cluster_slots_assigned"); assigned < clusterSlots {
+		raise(healthchecks.CRITICAL, fmt.Sprintf("%d slots are unassigned", clusterSlots-assigned))
+	}
+
+	if failed := infoInt(info, "cluster_slots_fail"); failed > 0 {
+		raise(healthchecks.CRITICAL, fmt.Sprintf("%d slots are failing", failed))
+	} else if suspected := infoInt(info, "cluster_slots_pfail"); suspected > 0 {
+		raise(healthchecks.WARNING, fmt.Sprintf("%d slots are suspected to fail", suspected))
+	}
+
+	nodes := parseClusterNodes(rawNodes)
+	nodeStatuses := make([]healthchecks.Status, 0, len(nodes))
+	failedMasters, failedReplicas := 0, 0
+	for _, node := range nodes {
+		nodeStatus := healthchecks.Status{
+			Description: fmt.Sprintf("%s (%s)", node.address, node.role()),
+			Result:      healthchecks.OK,
+			Details:     fmt.Sprintf("link=%s slots=%s", node.linkState, strings.Join(node.slots, ",")),
+		}
+
+		switch {
+		case node.flags["fail"] && node.flags["master"]:
+			nodeStatus.Result = healthchecks.CRITICAL
+			nodeStatus.Details = fmt.Sprintf("Node failed; %s", nodeStatus.Details)
+			failedMasters++
+		case node.flags["fail"]:
+			nodeStatus.Result = healthchecks.WARNING
+			nodeStatus.Details = fmt.Sprintf("Node failed; %s", nodeStatus.Details)
+			failedReplicas++
+		case node.flags["fail?"]:
+			nodeStatus.Result = healthchecks.WARNING
+			nodeStatus.Details = fmt.Sprintf("Node is suspected to fail; %s", nodeStatus.Details)
+		case node.linkState != "connected":
+			nodeStatus.Result = healthchecks.WARNING
+			nodeStatus.Details = fmt.Sprintf("Node is disconnected; %s", nodeStatus.Details)
+		}
+
+		nodeStatuses = append(nodeStatuses, nodeStatus)
+	}
+
+	if failedMasters > 0 {
+		raise(healthchecks.CRITICAL, fmt.Sprintf("%d masters failed", failedMasters))
+	}
+	if failedReplicas > 0 {
+		raise(healthchecks.WARNING, fmt.Sprintf("%d replicas failed", failedReplicas))
+	}
+
+	reasons = append(reasons, fmt.Sprintf("%d nodes, %s known", len(nodes), info["cluster_known_nodes"]))
+	s.Details = strings.Join(reasons, "; ")
+
+	return healthchecks.StatusList{StatusList: append([]healthchecks.Status{s}, nodeStatuses...)}
+}
+
+// Parse the output of `CLUSTER NODES`, one node per line:
+// <id> <ip:port@cport> <flags> <master> <ping-sent> <pong-recv> <config-epoch> <link-state> <slot> ... <slot>
+func parseClusterNodes(raw string) []clusterNode {
+	nodes := []clusterNode{}
+	for _, line := range strings.Split(raw, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			continue
+		}
+
+		flags := map[string]bool{}
+		for _, flag := range strings.Split(fields[2], ",") {
+			flags[flag] = true
+		}
+
+		nodes = append(nodes, clusterNode{
+			address:   strings.SplitN(fields[1], "@", 2)[0],
+			flags:     flags,
+			linkState: fields[7],
+			slots:     fields[8:],
+		})
+	}
+	return nodes
+}
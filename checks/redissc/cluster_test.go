@@ -0,0 +1,214 @@
+package redissc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/hootsuite/healthchecks"
+)
+
+const clusterInfoOK = "cluster_state:ok\r\ncluster_slots_assigned:16384\r\ncluster_slots_ok:16384\r\ncluster_slots_pfail:0\r\ncluster_slots_fail:0\r\ncluster_known_nodes:4\r\n"
+
+const clusterNodesOK = `07c37dfeb235213a872192d90877d0cd55635b91 127.0.0.1:30004@31004 slave e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 0 1426238317239 4 connected
+67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1 127.0.0.1:30002@31002 master - 0 1426238316232 2 connected 5461-10922
+e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 127.0.0.1:30001@31001 myself,master - 0 0 1 connected 0-5460
+292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f 127.0.0.1:30003@31003 master - 0 1426238318243 3 connected 10923-16383
+`
+
+func TestClusterOK(t *testing.T) {
+	redisClusterStatusChecker := RedisClusterStatusChecker{Client: ClusterRedis{info: clusterInfoOK, nodes: clusterNodesOK}}
+
+	s := redisClusterStatusChecker.CheckStatus("the cluster")
+
+	expected := []healthchecks.Status{
+		{Description: "the cluster", Result: healthchecks.OK, Details: "4 nodes, 4 known"},
+		{Description: "127.0.0.1:30004 (replica)", Result: healthchecks.OK, Details: "link=connected slots="},
+		{Description: "127.0.0.1:30002 (master)", Result: healthchecks.OK, Details: "link=connected slots=5461-10922"},
+		{Description: "127.0.0.1:30001 (master)", Result: healthchecks.OK, Details: "link=connected slots=0-5460"},
+		{Description: "127.0.0.1:30003 (master)", Result: healthchecks.OK, Details: "link=connected slots=10923-16383"},
+	}
+
+	if !reflect.DeepEqual(s.StatusList, expected) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, s.StatusList)
+	}
+}
+
+func TestClusterFailedReplica(t *testing.T) {
+	nodes := `07c37dfeb235213a872192d90877d0cd55635b91 127.0.0.1:30004@31004 slave,fail e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 0 1426238317239 4 disconnected
+e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 127.0.0.1:30001@31001 myself,master - 0 0 1 connected 0-16383
+`
+	redisClusterStatusChecker := RedisClusterStatusChecker{Client: ClusterRedis{info: clusterInfoOK, nodes: nodes}}
+
+	s := redisClusterStatusChecker.CheckStatus("the cluster")
+
+	if s.StatusList[0].Result != healthchecks.WARNING {
+		t.Errorf("Result should be `WARNING`, was `%s`", s.StatusList[0].Result)
+	}
+
+	eDetails := "1 replicas failed; 2 nodes, 4 known"
+	if s.StatusList[0].Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, s.StatusList[0].Details)
+	}
+
+	if s.StatusList[1].Result != healthchecks.WARNING || s.StatusList[1].Details != "Node failed; link=disconnected slots=" {
+		t.Errorf("Failed replica status should be `WARNING`, was `%v`", s.StatusList[1])
+	}
+}
+
+func TestClusterFail(t *testing.T) {
+	info := "cluster_state:fail\r\ncluster_slots_assigned:10923\r\ncluster_slots_fail:5461\r\ncluster_known_nodes:2\r\n"
+	nodes := `67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1 127.0.0.1:30002@31002 master,fail - 0 1426238316232 2 disconnected 5461-10922
+e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 127.0.0.1:30001@31001 myself,master - 0 0 1 connected 0-5460
+`
+	redisClusterStatusChecker := RedisClusterStatusChecker{Client: ClusterRedis{info: info, nodes: nodes}}
+
+	s := redisClusterStatusChecker.CheckStatus("the cluster")
+
+	if s.StatusList[0].Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", s.StatusList[0].Result)
+	}
+
+	eDetails := "Cluster state is `fail`; 5461 slots are unassigned; 5461 slots are failing; 1 masters failed; 2 nodes, 2 known"
+	if s.StatusList[0].Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, s.StatusList[0].Details)
+	}
+
+	if s.StatusList[1].Result != healthchecks.CRITICAL {
+		t.Errorf("Failed master status should be `CRITICAL`, was `%v`", s.StatusList[1])
+	}
+}
+
+func TestClusterError(t *testing.T) {
+	redisClusterStatusChecker := RedisClusterStatusChecker{Client: ClusterRedis{err: errors.New("ERR This instance has cluster support disabled")}}
+
+	s := redisClusterStatusChecker.CheckStatus("the cluster")
+
+	if len(s.StatusList) != 1 || s.StatusList[0].Result != healthchecks.CRITICAL {
+		t.Errorf("Status should be a single `CRITICAL` status, was `%v`", s.StatusList)
+	}
+}
+
+func TestSentinelOK(t *testing.T) {
+	redisSentinelStatusChecker := RedisSentinelStatusChecker{Client: newSentinelRedis(), MasterName: "mymaster"}
+
+	s := redisSentinelStatusChecker.CheckStatus("the sentinel")
+
+	expected := []healthchecks.Status{
+		{Description: "the sentinel", Result: healthchecks.OK, Details: "master mymaster at 10.0.0.1:6379 with 1 replicas and 2 other sentinels, quorum 2"},
+		{Description: "10.0.0.1:6379 (master)", Result: healthchecks.OK, Details: "flags=master"},
+		{Description: "10.0.0.2:6379 (replica)", Result: healthchecks.OK, Details: "flags=slave"},
+		{Description: "10.0.0.11:26379 (sentinel)", Result: healthchecks.OK, Details: "flags=sentinel"},
+		{Description: "10.0.0.12:26379 (sentinel)", Result: healthchecks.OK, Details: "flags=sentinel"},
+	}
+
+	if !reflect.DeepEqual(s.StatusList, expected) {
+		t.Errorf("Status response should be `%v`, was: `%v`", expected, s.StatusList)
+	}
+}
+
+func TestSentinelMasterDown(t *testing.T) {
+	client := newSentinelRedis()
+	client.master["flags"] = "master,s_down,o_down"
+	redisSentinelStatusChecker := RedisSentinelStatusChecker{Client: client, MasterName: "mymaster"}
+
+	s := redisSentinelStatusChecker.CheckStatus("the sentinel")
+
+	if s.StatusList[0].Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", s.StatusList[0].Result)
+	}
+
+	if s.StatusList[1].Details != "Node is objectively down; flags=master,s_down,o_down" {
+		t.Errorf("Master details should report it is down, was `%s`", s.StatusList[1].Details)
+	}
+}
+
+func TestSentinelNoQuorum(t *testing.T) {
+	client := newSentinelRedis()
+	client.quorumErr = errors.New("NOQUORUM 1 usable Sentinels. Not enough available Sentinels to reach the specified quorum for this master")
+	client.sentinels[1]["flags"] = "sentinel,s_down"
+	redisSentinelStatusChecker := RedisSentinelStatusChecker{Client: client, MasterName: "mymaster"}
+
+	s := redisSentinelStatusChecker.CheckStatus("the sentinel")
+
+	if s.StatusList[0].Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", s.StatusList[0].Result)
+	}
+
+	eDetails := "Quorum not satisfied: NOQUORUM 1 usable Sentinels. Not enough available Sentinels to reach the specified quorum for this master; " +
+		"1 of 2 other sentinels are down; " +
+		"master mymaster at 10.0.0.1:6379 with 1 replicas and 2 other sentinels, quorum 2"
+	if s.StatusList[0].Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, s.StatusList[0].Details)
+	}
+}
+
+func TestSentinelReplicaDown(t *testing.T) {
+	client := newSentinelRedis()
+	client.replicas[0]["master-link-status"] = "err"
+	redisSentinelStatusChecker := RedisSentinelStatusChecker{Client: client, MasterName: "mymaster"}
+
+	s := redisSentinelStatusChecker.CheckStatus("the sentinel")
+
+	if s.StatusList[0].Result != healthchecks.WARNING {
+		t.Errorf("Result should be `WARNING`, was `%s`", s.StatusList[0].Result)
+	}
+
+	if s.StatusList[2].Result != healthchecks.WARNING {
+		t.Errorf("Replica status should be `WARNING`, was `%v`", s.StatusList[2])
+	}
+}
+
+// Mocks
+type ClusterRedis struct {
+	info  string
+	nodes string
+	err   error
+}
+
+func (r ClusterRedis) ClusterInfo() (string, error) {
+	return r.info, r.err
+}
+
+func (r ClusterRedis) ClusterNodes() (string, error) {
+	return r.nodes, r.err
+}
+
+type SentinelRedis struct {
+	master    map[string]string
+	replicas  []map[string]string
+	sentinels []map[string]string
+	quorumErr error
+}
+
+func newSentinelRedis() *SentinelRedis {
+	return &SentinelRedis{
+		master: map[string]string{"name": "mymaster", "ip": "10.0.0.1", "port": "6379", "flags": "master", "quorum": "2"},
+		replicas: []map[string]string{
+			{"ip": "10.0.0.2", "port": "6379", "flags": "slave", "master-link-status": "ok"},
+		},
+		sentinels: []map[string]string{
+			{"ip": "10.0.0.11", "port": "26379", "flags": "sentinel"},
+			{"ip": "10.0.0.12", "port": "26379", "flags": "sentinel"},
+		},
+	}
+}
+
+func (r *SentinelRedis) SentinelMaster(name string) (map[string]string, error) {
+	return r.master, nil
+}
+
+func (r *SentinelRedis) SentinelReplicas(name string) ([]map[string]string, error) {
+	return r.replicas, nil
+}
+
+func (r *SentinelRedis) SentinelSentinels(name string) ([]map[string]string, error) {
+	return r.sentinels, nil
+}
+
+func (r *SentinelRedis) SentinelCkquorum(name string) (string, error) {
+	if r.quorumErr != nil {
+		return "", r.quorumErr
+	}
+	return "OK 3 usable Sentinels. Quorum and failover authorization can be reached", nil
+}
@@ -0,0 +1,224 @@
+package redissc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// A thin Redis wrapper that can also read the `INFO` output, used for mocks / tests
+type RedisInfoClient interface {
+	RedisClient
+	// Returns the raw output of `INFO`
+	Info() (string, error)
+}
+
+// InfoThresholds are the thresholds a RedisInfoStatusChecker evaluates the `INFO` output against. A threshold is
+// disabled when 0 or empty.
+//
+// The rejected connections thresholds apply to the number of connections rejected since the previous check, which
+// is tracked in the InfoThresholds. The first check only records the counter, so connections rejected before the
+// service started are not reported. An InfoThresholds should therefore not be shared between checkers.
+type InfoThresholds struct {
+	WarningMemoryRatio          float64 // Ratio of used_memory to maxmemory, only evaluated if maxmemory is set
+	CriticalMemoryRatio         float64 // Ratio of used_memory to maxmemory, only evaluated if maxmemory is set
+	WarningConnectedClients     int64   // Number of connected clients
+	CriticalConnectedClients    int64   // Number of connected clients
+	WarningRejectedConnections  int64   // Number of connections rejected since the previous check
+	CriticalRejectedConnections int64   // Number of connections rejected since the previous check
+	ExpectedRole                string  // Expected replication role, `master` or `slave`
+
+	mu                    sync.Mutex
+	lastRejected          int64
+	lastRejectedAvailable bool
+}
+
+// RedisInfoStatusChecker checks that Redis responds to `PING` and evaluates its `INFO` output: memory usage,
+// connected and rejected clients, the replication role and master link status, and persistence errors.
+//
+// A replica whose link to its master is down, or a failed RDB save or AOF write, results in a CRITICAL status unless
+// configured otherwise. A level other than OK, WARN or CRIT is a configuration error reported as CRITICAL.
+type RedisInfoStatusChecker struct {
+	Client     RedisInfoClient
+	Thresholds *InfoThresholds // Optional thresholds, only replication and persistence errors are checked if nil
+	// Optional level of a replica whose link to its master is down, defaults to CRITICAL
+	ReplicationFailureLevel healthchecks.AlertLevel
+	// Optional level of a failed RDB save or AOF write, defaults to CRITICAL
+	PersistenceFailureLevel healthchecks.AlertLevel
+	// Optional level of a role other than the ExpectedRole of the Thresholds, defaults to WARNING
+	RoleMismatchLevel healthchecks.AlertLevel
+}
+
+func (r RedisInfoStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	levels := []struct {
+		field string
+		level healthchecks.AlertLevel
+	}{
+		{"ReplicationFailureLevel", r.ReplicationFailureLevel},
+		{"PersistenceFailureLevel", r.PersistenceFailureLevel},
+		{"RoleMismatchLevel", r.RoleMismatchLevel},
+	}
+	for _, l := range levels {
+		if !validLevel(l.level) {
+			return healthchecks.StatusList{StatusList: []healthchecks.Status{{
+				Description: name,
+				Result:      healthchecks.CRITICAL,
+				Details:     fmt.Sprintf("Invalid %s `%s`, must be one of OK, WARN or CRIT", l.field, l.level),
+			}}}
+		}
+	}
+
+	pingStatus := RedisStatusChecker{Client: r.Client}.CheckStatus(name)
+	if pingStatus.StatusList[0].Result != healthchecks.OK {
+		return pingStatus
+	}
+
+	s := healthchecks.Status{
+		Description: name,
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	raw, err := r.Client.Info()
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = err.Error()
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	info := ParseInfo(raw)
+	reasons := []string{}
+	raise := func(level healthchecks.AlertLevel, reason string) {
+		s.Result = healthchecks.Worst(s.Result, level)
+		reasons = append(reasons, reason)
+	}
+	replicationFailureLevel := levelOrDefault(r.ReplicationFailureLevel, healthchecks.CRITICAL)
+	persistenceFailureLevel := levelOrDefault(r.PersistenceFailureLevel, healthchecks.CRITICAL)
+
+	// Replication
+	role := info["role"]
+	if role == "slave" && info["master_link_status"] != "up" {
+		raise(replicationFailureLevel, fmt.Sprintf("Link to master %s:%s is %s", info["master_host"], info["master_port"], info["master_link_status"]))
+	}
+
+	// Persistence
+	if status, ok := info["rdb_last_bgsave_status"]; ok && status != "ok" {
+		raise(persistenceFailureLevel, fmt.Sprintf("Last RDB save failed with status `%s`", status))
+	}
+	if info["aof_enabled"] == "1" {
+		if status, ok := info["aof_last_write_status"]; ok && status != "ok" {
+			raise(persistenceFailureLevel, fmt.Sprintf("Last AOF write failed with status `%s`", status))
+		}
+		if status, ok := info["aof_last_bgrewrite_status"]; ok && status != "ok" {
+			raise(persistenceFailureLevel, fmt.Sprintf("Last AOF rewrite failed with status `%s`", status))
+		}
+	}
+
+	usedMemory := infoInt(info, "used_memory")
+	maxMemory := infoInt(info, "maxmemory")
+	connectedClients := infoInt(info, "connected_clients")
+	rejectedConnections := infoInt(info, "rejected_connections")
+
+	if t := r.Thresholds; t != nil {
+		if t.ExpectedRole != "" && role != t.ExpectedRole {
+			raise(levelOrDefault(r.RoleMismatchLevel, healthchecks.WARNING), fmt.Sprintf("Role is %s, expecting %s", role, t.ExpectedRole))
+		}
+
+		if maxMemory > 0 {
+			ratio := float64(usedMemory) / float64(maxMemory)
+			memory := fmt.Sprintf("Using %.0f%% of maxmemory", ratio*100)
+			if t.CriticalMemoryRatio > 0 && ratio >= t.CriticalMemoryRatio {
+				raise(healthchecks.CRITICAL, fmt.Sprintf("%s exceeds critical threshold of %.0f%%", memory, t.CriticalMemoryRatio*100))
+			} else if t.WarningMemoryRatio > 0 && ratio >= t.WarningMemoryRatio {
+				raise(healthchecks.WARNING, fmt.Sprintf("%s exceeds warning threshold of %.0f%%", memory, t.WarningMemoryRatio*100))
+			}
+		}
+
+		clients := fmt.Sprintf("%d connected clients", connectedClients)
+		if t.CriticalConnectedClients > 0 && connectedClients >= t.CriticalConnectedClients {
+			raise(healthchecks.CRITICAL, fmt.Sprintf("%s exceeds critical threshold of %d", clients, t.CriticalConnectedClients))
+		} else if t.WarningConnectedClients > 0 && connectedClients >= t.WarningConnectedClients {
+			raise(healthchecks.WARNING, fmt.Sprintf("%s exceeds warning threshold of %d", clients, t.WarningConnectedClients))
+		}
+
+		rejected := t.rejectedSinceLastCheck(rejectedConnections)
+		rejectedDetails := fmt.Sprintf("%d new rejected connections", rejected)
+		if t.CriticalRejectedConnections > 0 && rejected >= t.CriticalRejectedConnections {
+			raise(healthchecks.CRITICAL, fmt.Sprintf("%s exceeds critical threshold of %d", rejectedDetails, t.CriticalRejectedConnections))
+		} else if t.WarningRejectedConnections > 0 && rejected >= t.WarningRejectedConnections {
+			raise(healthchecks.WARNING, fmt.Sprintf("%s exceeds warning threshold of %d", rejectedDetails, t.WarningRejectedConnections))
+		}
+	}
+
+	reasons = append(reasons, fmt.Sprintf(
+		"role=%s used_memory=%d maxmemory=%d connected_clients=%d rejected_connections=%d",
+		role,
+		usedMemory,
+		maxMemory,
+		connectedClients,
+		rejectedConnections,
+	))
+	s.Details = strings.Join(reasons, "; ")
+
+	return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+}
+
+func (t *InfoThresholds) rejectedSinceLastCheck(rejected int64) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// The first check only records the baseline, the counter covers the whole lifetime of Redis
+	var since int64
+	if t.lastRejectedAvailable {
+		since = rejected - t.lastRejected
+		// The counter is reset by a restart or `CONFIG RESETSTAT`
+		if rejected < t.lastRejected {
+			since = rejected
+		}
+	}
+	t.lastRejected = rejected
+	t.lastRejectedAvailable = true
+
+	return since
+}
+
+// ParseInfo parses the output of `INFO` into a map of fields, ignoring section headers and blank lines.
+func ParseInfo(raw string) map[string]string {
+	info := map[string]string{}
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) == 2 {
+			info[parts[0]] = parts[1]
+		}
+	}
+	return info
+}
+
+// Whether level is empty, so defaulted, or one of OK, WARN or CRIT
+func validLevel(level healthchecks.AlertLevel) bool {
+	switch level {
+	case "", healthchecks.OK, healthchecks.WARNING, healthchecks.CRITICAL:
+		return true
+	}
+	return false
+}
+
+func levelOrDefault(level healthchecks.AlertLevel, defaultLevel healthchecks.AlertLevel) healthchecks.AlertLevel {
+	if level == "" {
+		return defaultLevel
+	}
+	return level
+}
+
+func infoInt(info map[string]string, key string) int64 {
+	value, _ := strconv.ParseInt(info[key], 10, 64)
+	return value
+}
@@ -0,0 +1,193 @@
+package redissc
+
+import (
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/hootsuite/healthchecks"
+)
+
+const infoMaster = `# Server
+redis_version:7.2.4
+
+# Clients
+connected_clients:10
+
+# Memory
+used_memory:500
+maxmemory:1000
+
+# Persistence
+rdb_last_bgsave_status:ok
+aof_enabled:0
+
+# Stats
+rejected_connections:3
+
+# Replication
+role:master
+connected_slaves:1
+`
+
+const infoBrokenReplica = "# Replication\r\nrole:slave\r\nmaster_host:10.0.0.1\r\nmaster_port:6379\r\nmaster_link_status:down\r\n" +
+	"# Persistence\r\nrdb_last_bgsave_status:err\r\naof_enabled:1\r\naof_last_write_status:err\r\naof_last_bgrewrite_status:ok\r\n"
+
+func TestInfoOK(t *testing.T) {
+	redisInfoStatusChecker := RedisInfoStatusChecker{Client: InfoRedis{info: infoMaster}}
+
+	s := redisInfoStatusChecker.CheckStatus("the redis")
+
+	if len(s.StatusList) != 1 {
+		t.Errorf("Length of StatusList should be 1, was %d", len(s.StatusList))
+	}
+
+	actual := s.StatusList[0]
+	if actual.Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was `%s`", actual.Result)
+	}
+
+	eDetails := "role=master used_memory=500 maxmemory=1000 connected_clients=10 rejected_connections=3"
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestInfoThresholds(t *testing.T) {
+	redisInfoStatusChecker := RedisInfoStatusChecker{
+		Client: InfoRedis{info: infoMaster},
+		Thresholds: &InfoThresholds{
+			WarningMemoryRatio:       0.5,
+			CriticalMemoryRatio:      0.9,
+			WarningConnectedClients:  10,
+			CriticalConnectedClients: 100,
+			ExpectedRole:             "master",
+		},
+	}
+
+	actual := redisInfoStatusChecker.CheckStatus("the redis").StatusList[0]
+	if actual.Result != healthchecks.WARNING {
+		t.Errorf("Result should be `WARNING`, was `%s`", actual.Result)
+	}
+
+	eDetails := "Using 50% of maxmemory exceeds warning threshold of 50%; " +
+		"10 connected clients exceeds warning threshold of 10; " +
+		"role=master used_memory=500 maxmemory=1000 connected_clients=10 rejected_connections=3"
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestInfoRejectedConnections(t *testing.T) {
+	thresholds := &InfoThresholds{WarningRejectedConnections: 1, CriticalRejectedConnections: 5}
+
+	// The first check only records the connections rejected since Redis started
+	actual := RedisInfoStatusChecker{Client: InfoRedis{info: "rejected_connections:300"}, Thresholds: thresholds}.CheckStatus("the redis").StatusList[0]
+	if actual.Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was `%s`", actual.Result)
+	}
+
+	actual = RedisInfoStatusChecker{Client: InfoRedis{info: "rejected_connections:302"}, Thresholds: thresholds}.CheckStatus("the redis").StatusList[0]
+	if actual.Result != healthchecks.WARNING {
+		t.Errorf("Result should be `WARNING`, was `%s`", actual.Result)
+	}
+
+	// A restart resets the counter
+	actual = RedisInfoStatusChecker{Client: InfoRedis{info: "rejected_connections:3"}, Thresholds: thresholds}.CheckStatus("the redis").StatusList[0]
+	if actual.Result != healthchecks.WARNING {
+		t.Errorf("Result should be `WARNING`, was `%s`", actual.Result)
+	}
+
+	actual = RedisInfoStatusChecker{Client: InfoRedis{info: "rejected_connections:10"}, Thresholds: thresholds}.CheckStatus("the redis").StatusList[0]
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+}
+
+func TestInfoReplicationAndPersistenceErrors(t *testing.T) {
+	redisInfoStatusChecker := RedisInfoStatusChecker{
+		Client:     InfoRedis{info: infoBrokenReplica},
+		Thresholds: &InfoThresholds{ExpectedRole: "master"},
+	}
+
+	actual := redisInfoStatusChecker.CheckStatus("the redis").StatusList[0]
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+
+	eDetails := "Link to master 10.0.0.1:6379 is down; " +
+		"Last RDB save failed with status `err`; " +
+		"Last AOF write failed with status `err`; " +
+		"Role is slave, expecting master; " +
+		"role=slave used_memory=0 maxmemory=0 connected_clients=0 rejected_connections=0"
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestInfoConfiguredLevels(t *testing.T) {
+	redisInfoStatusChecker := RedisInfoStatusChecker{
+		Client:                  InfoRedis{info: infoBrokenReplica},
+		Thresholds:              &InfoThresholds{ExpectedRole: "master"},
+		ReplicationFailureLevel: healthchecks.WARNING,
+		PersistenceFailureLevel: healthchecks.WARNING,
+		RoleMismatchLevel:       healthchecks.OK,
+	}
+
+	actual := redisInfoStatusChecker.CheckStatus("the redis").StatusList[0]
+	if actual.Result != healthchecks.WARNING {
+		t.Errorf("Result should be `WARNING`, was `%s`", actual.Result)
+	}
+
+	redisInfoStatusChecker.RoleMismatchLevel = healthchecks.CRITICAL
+
+	actual = redisInfoStatusChecker.CheckStatus("the redis").StatusList[0]
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+}
+
+func TestInfoInvalidLevel(t *testing.T) {
+	redisInfoStatusChecker := RedisInfoStatusChecker{Client: InfoRedis{info: infoMaster}, PersistenceFailureLevel: "WARNING"}
+
+	actual := redisInfoStatusChecker.CheckStatus("the redis").StatusList[0]
+	eDetails := "Invalid PersistenceFailureLevel `WARNING`, must be one of OK, WARN or CRIT"
+	if actual.Result != healthchecks.CRITICAL || actual.Details != eDetails {
+		t.Errorf("Status should be `CRITICAL` with `%s`, was `%s` with `%s`", eDetails, actual.Result, actual.Details)
+	}
+}
+
+func TestInfoPingError(t *testing.T) {
+	redisInfoStatusChecker := RedisInfoStatusChecker{Client: InfoRedis{pingErr: errors.New("An error message")}}
+
+	actual := redisInfoStatusChecker.CheckStatus("the redis").StatusList[0]
+	if actual.Result != healthchecks.CRITICAL || actual.Details != "An error message" {
+		t.Errorf("Status should be `CRITICAL` with `An error message`, was `%s` with `%s`", actual.Result, actual.Details)
+	}
+}
+
+func TestInfoError(t *testing.T) {
+	redisInfoStatusChecker := RedisInfoStatusChecker{Client: InfoRedis{infoErr: errors.New("ERR unknown command")}}
+
+	actual := redisInfoStatusChecker.CheckStatus("the redis").StatusList[0]
+	if actual.Result != healthchecks.CRITICAL || actual.Details != "ERR unknown command" {
+		t.Errorf("Status should be `CRITICAL` with `ERR unknown command`, was `%s` with `%s`", actual.Result, actual.Details)
+	}
+}
+
+// Mocks
+type InfoRedis struct {
+	info    string
+	pingErr error
+	infoErr error
+}
+
+func (r InfoRedis) Ping() (string, error) {
+	if r.pingErr != nil {
+		return "", r.pingErr
+	}
+	return "PONG", nil
+}
+
+func (r InfoRedis) Info() (string, error) {
+	return r.info, r.infoErr
+}
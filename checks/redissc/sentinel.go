@@ -0,0 +1,141 @@
+package redissc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// A thin Redis Sentinel wrapper used for mocks / tests. Each map holds the field/value pairs Sentinel returns.
+type RedisSentinelClient interface {
+	// Returns the fields of `SENTINEL MASTER <name>`
+	SentinelMaster(name string) (map[string]string, error)
+	// Returns the fields of each entry of `SENTINEL REPLICAS <name>`
+	SentinelReplicas(name string) ([]map[string]string, error)
+	// Returns the fields of each entry of `SENTINEL SENTINELS <name>`
+	SentinelSentinels(name string) ([]map[string]string, error)
+	// Returns the reply of `SENTINEL CKQUORUM <name>`, or an error if the quorum cannot be reached
+	SentinelCkquorum(name string) (string, error)
+}
+
+// RedisSentinelStatusChecker checks a master monitored by Redis Sentinel.
+//
+// The returned StatusList starts with the overall status followed by one entry for the master, each replica and each
+// other Sentinel. The overall status is CRITICAL if the master is down or the Sentinels cannot reach their quorum, and
+// WARN if a replica or another Sentinel is down.
+type RedisSentinelStatusChecker struct {
+	Client     RedisSentinelClient
+	MasterName string
+}
+
+func (r RedisSentinelStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	s := healthchecks.Status{
+		Description: name,
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	master, err := r.Client.SentinelMaster(r.MasterName)
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = err.Error()
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	reasons := []string{}
+	raise := func(level healthchecks.AlertLevel, reason string) {
+		s.Result = healthchecks.Worst(s.Result, level)
+		reasons = append(reasons, reason)
+	}
+
+	masterStatus := sentinelNodeStatus("master", master, healthchecks.CRITICAL)
+	if masterStatus.Result != healthchecks.OK {
+		raise(healthchecks.CRITICAL, fmt.Sprintf("Master %s is down", r.MasterName))
+	}
+
+	quorum, err := r.Client.SentinelCkquorum(r.MasterName)
+	if err != nil {
+		raise(healthchecks.CRITICAL, fmt.Sprintf("Quorum not satisfied: %s", err.Error()))
+	} else if !strings.HasPrefix(quorum, "OK") {
+		raise(healthchecks.CRITICAL, fmt.Sprintf("Quorum not satisfied: %s", quorum))
+	}
+
+	nodeStatuses := []healthchecks.Status{masterStatus}
+
+	replicas, err := r.Client.SentinelReplicas(r.MasterName)
+	if err != nil {
+		raise(healthchecks.WARNING, fmt.Sprintf("Error listing replicas: %s", err.Error()))
+	}
+	replicasDown := 0
+	for _, replica := range replicas {
+		replicaStatus := sentinelNodeStatus("replica", replica, healthchecks.WARNING)
+		if replicaStatus.Result != healthchecks.OK {
+			replicasDown++
+		}
+		nodeStatuses = append(nodeStatuses, replicaStatus)
+	}
+	if replicasDown > 0 {
+		raise(healthchecks.WARNING, fmt.Sprintf("%d of %d replicas are down", replicasDown, len(replicas)))
+	}
+
+	sentinels, err := r.Client.SentinelSentinels(r.MasterName)
+	if err != nil {
+		raise(healthchecks.WARNING, fmt.Sprintf("Error listing sentinels: %s", err.Error()))
+	}
+	sentinelsDown := 0
+	for _, sentinel := range sentinels {
+		sentinelStatus := sentinelNodeStatus("sentinel", sentinel, healthchecks.WARNING)
+		if sentinelStatus.Result != healthchecks.OK {
+			sentinelsDown++
+		}
+		nodeStatuses = append(nodeStatuses, sentinelStatus)
+	}
+	if sentinelsDown > 0 {
+		raise(healthchecks.WARNING, fmt.Sprintf("%d of %d other sentinels are down", sentinelsDown, len(sentinels)))
+	}
+
+	reasons = append(reasons, fmt.Sprintf(
+		"master %s at %s:%s with %d replicas and %d other sentinels, quorum %s",
+		r.MasterName,
+		master["ip"],
+		master["port"],
+		len(replicas),
+		len(sentinels),
+		master["quorum"],
+	))
+	s.Details = strings.Join(reasons, "; ")
+
+	return healthchecks.StatusList{StatusList: append([]healthchecks.Status{s}, nodeStatuses...)}
+}
+
+// Build the status of a single node from its Sentinel fields, using downLevel if the node is down
+func sentinelNodeStatus(role string, fields map[string]string, downLevel healthchecks.AlertLevel) healthchecks.Status {
+	s := healthchecks.Status{
+		Description: fmt.Sprintf("%s:%s (%s)", fields["ip"], fields["port"], role),
+		Result:      healthchecks.OK,
+		Details:     fmt.Sprintf("flags=%s", fields["flags"]),
+	}
+
+	flags := map[string]bool{}
+	for _, flag := range strings.Split(fields["flags"], ",") {
+		flags[flag] = true
+	}
+
+	switch {
+	case flags["o_down"]:
+		s.Result = downLevel
+		s.Details = fmt.Sprintf("Node is objectively down; %s", s.Details)
+	case flags["s_down"]:
+		s.Result = downLevel
+		s.Details = fmt.Sprintf("Node is subjectively down; %s", s.Details)
+	case flags["disconnected"]:
+		s.Result = downLevel
+		s.Details = fmt.Sprintf("Node is disconnected; %s", s.Details)
+	case role == "replica" && fields["master-link-status"] != "" && fields["master-link-status"] != "ok":
+		s.Result = healthchecks.WARNING
+		s.Details = fmt.Sprintf("Link to master is %s; %s", fields["master-link-status"], s.Details)
+	}
+
+	return s
+}
@@ -0,0 +1,205 @@
+package runtimesc
+
+import (
+	"fmt"
+	"math"
+	"runtime/metrics"
+	"strings"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+	"github.com/hootsuite/healthchecks/internal/units"
+)
+
+// Names of the runtime/metrics samples read by ReadSample
+const (
+	metricGoroutines   = "/sched/goroutines:goroutines"
+	metricHeapObjects  = "/memory/classes/heap/objects:bytes"
+	metricHeapUnused   = "/memory/classes/heap/unused:bytes"
+	metricGCPauses     = "/sched/pauses/total/gc:seconds"
+	metricGCCPUSeconds = "/cpu/classes/gc/total:cpu-seconds"
+	metricCPUSeconds   = "/cpu/classes/total:cpu-seconds"
+)
+
+// Sample of the Go runtime metrics
+type Sample struct {
+	Goroutines    uint64        // Number of live goroutines
+	HeapInUse     uint64        // Bytes of heap spans in use, as runtime.MemStats.HeapInuse
+	GCPauseP99    time.Duration // 99th percentile of the stop-the-world GC pauses since the process started
+	GCCPUFraction float64       // Fraction of the CPU time spent in the GC since the process started
+}
+
+// ReadSample reads the current Go runtime metrics
+func ReadSample() Sample {
+	samples := []metrics.Sample{
+		{Name: metricGoroutines},
+		{Name: metricHeapObjects},
+		{Name: metricHeapUnused},
+		{Name: metricGCPauses},
+		{Name: metricGCCPUSeconds},
+		{Name: metricCPUSeconds},
+	}
+	metrics.Read(samples)
+
+	values := map[string]metrics.Value{}
+	for _, sample := range samples {
+		values[sample.Name] = sample.Value
+	}
+
+	s := Sample{
+		Goroutines: uint64Value(values[metricGoroutines]),
+		HeapInUse:  uint64Value(values[metricHeapObjects]) + uint64Value(values[metricHeapUnused]),
+	}
+
+	if pauses := values[metricGCPauses]; pauses.Kind() == metrics.KindFloat64Histogram {
+		s.GCPauseP99 = time.Duration(percentile(pauses.Float64Histogram(), 0.99) * float64(time.Second))
+	}
+
+	if cpu := float64Value(values[metricCPUSeconds]); cpu > 0 {
+		s.GCCPUFraction = float64Value(values[metricGCCPUSeconds]) / cpu
+	}
+
+	return s
+}
+
+// RuntimeStatusChecker checks the Go runtime of the process: goroutine count, heap in use, GC pause p99 and GC CPU
+// fraction.
+//
+// Each metric is checked against absolute thresholds, and the goroutine count and heap against their growth since
+// Baseline, as a multiple of the baseline value. A threshold is disabled when 0, growth thresholds are ignored when
+// Baseline is nil. Use NewStatusEndpoint to take the baseline once the service started.
+type RuntimeStatusChecker struct {
+	Baseline                *Sample       // Optional sample the growth is measured against
+	WarningGoroutines       uint64        // Number of goroutines
+	CriticalGoroutines      uint64        // Number of goroutines
+	WarningHeapInUse        uint64        // Bytes of heap in use
+	CriticalHeapInUse       uint64        // Bytes of heap in use
+	WarningGCPauseP99       time.Duration // 99th percentile of the GC pauses
+	CriticalGCPauseP99      time.Duration // 99th percentile of the GC pauses
+	WarningGCCPUFraction    float64       // Fraction of the CPU time spent in the GC, from 0 to 1
+	CriticalGCCPUFraction   float64       // Fraction of the CPU time spent in the GC, from 0 to 1
+	WarningGoroutineGrowth  float64       // Number of goroutines as a multiple of the baseline, such as 2 for twice as many
+	CriticalGoroutineGrowth float64       // Number of goroutines as a multiple of the baseline
+	WarningHeapGrowth       float64       // Heap in use as a multiple of the baseline
+	CriticalHeapGrowth      float64       // Heap in use as a multiple of the baseline
+}
+
+// NewStatusEndpoint creates an `internal` StatusEndpoint checking the Go runtime. The baseline of the checker is taken
+// now if not set, so it should be called once the service started.
+func NewStatusEndpoint(name string, slug string, checker RuntimeStatusChecker) healthchecks.StatusEndpoint {
+	if checker.Baseline == nil {
+		baseline := ReadSample()
+		checker.Baseline = &baseline
+	}
+
+	return healthchecks.StatusEndpoint{
+		Name:          name,
+		Slug:          slug,
+		Type:          "internal",
+		IsTraversable: false,
+		StatusCheck:   checker,
+		TraverseCheck: nil,
+	}
+}
+
+func (r RuntimeStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	return healthchecks.StatusList{StatusList: []healthchecks.Status{r.checkSample(name, ReadSample())}}
+}
+
+func (r RuntimeStatusChecker) checkSample(name string, sample Sample) healthchecks.Status {
+	result := healthchecks.OK
+	reasons := []string{}
+	raise := func(level healthchecks.AlertLevel, reason string) {
+		result = healthchecks.Worst(result, level)
+		reasons = append(reasons, reason)
+	}
+	check := func(label string, value float64, warning float64, critical float64, format func(float64) string) {
+		if critical > 0 && value >= critical {
+			raise(healthchecks.CRITICAL, fmt.Sprintf("%s of %s exceeds critical threshold of %s", label, format(value), format(critical)))
+		} else if warning > 0 && value >= warning {
+			raise(healthchecks.WARNING, fmt.Sprintf("%s of %s exceeds warning threshold of %s", label, format(value), format(warning)))
+		}
+	}
+
+	formatCount := func(v float64) string { return fmt.Sprintf("%.0f", v) }
+	formatSize := func(v float64) string { return units.FormatBytes(uint64(v)) }
+	formatDuration := func(v float64) string { return time.Duration(v).String() }
+	formatPercent := func(v float64) string { return fmt.Sprintf("%.2f%%", v*100) }
+	formatGrowth := func(v float64) string { return fmt.Sprintf("%.1fx", v) }
+
+	check("Goroutines", float64(sample.Goroutines), float64(r.WarningGoroutines), float64(r.CriticalGoroutines), formatCount)
+	check("Heap in use", float64(sample.HeapInUse), float64(r.WarningHeapInUse), float64(r.CriticalHeapInUse), formatSize)
+	check("GC pause p99", float64(sample.GCPauseP99), float64(r.WarningGCPauseP99), float64(r.CriticalGCPauseP99), formatDuration)
+	check("GC CPU fraction", sample.GCCPUFraction, r.WarningGCCPUFraction, r.CriticalGCCPUFraction, formatPercent)
+
+	details := fmt.Sprintf(
+		"goroutines=%d heap_inuse=%s gc_pause_p99=%s gc_cpu_fraction=%s",
+		sample.Goroutines,
+		units.FormatBytes(sample.HeapInUse),
+		sample.GCPauseP99,
+		formatPercent(sample.GCCPUFraction),
+	)
+
+	if r.Baseline != nil {
+		goroutineGrowth := growth(sample.Goroutines, r.Baseline.Goroutines)
+		heapGrowth := growth(sample.HeapInUse, r.Baseline.HeapInUse)
+
+		check("Goroutines growth", goroutineGrowth, r.WarningGoroutineGrowth, r.CriticalGoroutineGrowth, formatGrowth)
+		check("Heap growth", heapGrowth, r.WarningHeapGrowth, r.CriticalHeapGrowth, formatGrowth)
+
+		details += fmt.Sprintf(" goroutines_growth=%s heap_growth=%s", formatGrowth(goroutineGrowth), formatGrowth(heapGrowth))
+	}
+
+	return healthchecks.Status{
+		Description: name,
+		Result:      result,
+		Details:     strings.Join(append(reasons, details), "; "),
+	}
+}
+
+func uint64Value(value metrics.Value) uint64 {
+	if value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return value.Uint64()
+}
+
+func float64Value(value metrics.Value) float64 {
+	if value.Kind() != metrics.KindFloat64 {
+		return 0
+	}
+	return value.Float64()
+}
+
+// Get the upper boundary of the bucket holding the percentile, or its lower boundary if unbounded
+func percentile(histogram *metrics.Float64Histogram, p float64) float64 {
+	total := uint64(0)
+	for _, count := range histogram.Counts {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := uint64(math.Ceil(float64(total) * p))
+	cumulative := uint64(0)
+	for i, count := range histogram.Counts {
+		cumulative += count
+		if cumulative >= rank {
+			// Bucket i spans Buckets[i] to Buckets[i+1]
+			if upper := histogram.Buckets[i+1]; !math.IsInf(upper, 1) {
+				return upper
+			}
+			return histogram.Buckets[i]
+		}
+	}
+	return 0
+}
+
+// Get the value as a multiple of the baseline
+func growth(value uint64, baseline uint64) float64 {
+	if baseline == 0 {
+		return 1
+	}
+	return float64(value) / float64(baseline)
+}
@@ -0,0 +1,124 @@
+package runtimesc
+
+import (
+	"math"
+	"runtime/metrics"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+func TestRuntime(t *testing.T) {
+	s := RuntimeStatusChecker{}.CheckStatus("The runtime")
+
+	if len(s.StatusList) != 1 {
+		t.Fatalf("Length of StatusList should be 1, was %d", len(s.StatusList))
+	}
+	actual := s.StatusList[0]
+	if actual.Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was `%s`", actual.Result)
+	}
+	if !strings.HasPrefix(actual.Details, "goroutines=") || !strings.Contains(actual.Details, " gc_cpu_fraction=") {
+		t.Errorf("Details should report the runtime metrics, was `%s`", actual.Details)
+	}
+}
+
+func TestReadSample(t *testing.T) {
+	sample := ReadSample()
+
+	if sample.Goroutines == 0 {
+		t.Errorf("Goroutines should be greater than 0")
+	}
+	if sample.HeapInUse == 0 {
+		t.Errorf("HeapInUse should be greater than 0")
+	}
+}
+
+func TestRuntimeThresholds(t *testing.T) {
+	statusChecker := RuntimeStatusChecker{
+		WarningGoroutines:     100,
+		CriticalGoroutines:    1000,
+		WarningHeapInUse:      1 << 30,
+		WarningGCPauseP99:     time.Millisecond,
+		CriticalGCPauseP99:    10 * time.Millisecond,
+		WarningGCCPUFraction:  0.1,
+		CriticalGCCPUFraction: 0.25,
+	}
+	sample := Sample{Goroutines: 150, HeapInUse: 512 << 20, GCPauseP99: 20 * time.Millisecond, GCCPUFraction: 0.05}
+	actual := statusChecker.checkSample("The runtime", sample)
+
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+	eDetails := "Goroutines of 150 exceeds warning threshold of 100; " +
+		"GC pause p99 of 20ms exceeds critical threshold of 10ms; " +
+		"goroutines=150 heap_inuse=512.0MiB gc_pause_p99=20ms gc_cpu_fraction=5.00%"
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestRuntimeGrowth(t *testing.T) {
+	statusChecker := RuntimeStatusChecker{
+		Baseline:                &Sample{Goroutines: 10, HeapInUse: 4 << 20},
+		WarningGoroutineGrowth:  2,
+		CriticalGoroutineGrowth: 10,
+		WarningHeapGrowth:       2,
+		CriticalHeapGrowth:      4,
+	}
+	sample := Sample{Goroutines: 30, HeapInUse: 6 << 20}
+	actual := statusChecker.checkSample("The runtime", sample)
+
+	if actual.Result != healthchecks.WARNING {
+		t.Errorf("Result should be `WARNING`, was `%s`", actual.Result)
+	}
+	eDetails := "Goroutines growth of 3.0x exceeds warning threshold of 2.0x; " +
+		"goroutines=30 heap_inuse=6.0MiB gc_pause_p99=0s gc_cpu_fraction=0.00% goroutines_growth=3.0x heap_growth=1.5x"
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestNewStatusEndpoint(t *testing.T) {
+	statusEndpoint := NewStatusEndpoint("Go runtime", "runtime", RuntimeStatusChecker{CriticalGoroutineGrowth: 1000})
+
+	if statusEndpoint.Type != "internal" {
+		t.Errorf("Type should be `internal`, was `%s`", statusEndpoint.Type)
+	}
+	if statusEndpoint.Slug != "runtime" || statusEndpoint.IsTraversable {
+		t.Errorf("StatusEndpoint should be the non traversable `runtime`, was `%v`", statusEndpoint)
+	}
+
+	statusChecker := statusEndpoint.StatusCheck.(RuntimeStatusChecker)
+	if statusChecker.Baseline == nil {
+		t.Fatalf("Baseline should be set")
+	}
+
+	actual := statusChecker.CheckStatus("Go runtime").StatusList[0]
+	if actual.Result != healthchecks.OK || !strings.Contains(actual.Details, " goroutines_growth=") {
+		t.Errorf("Status should be `OK` with the growth, was `%v`", actual)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	histogram := &metrics.Float64Histogram{
+		Counts:  []uint64{90, 9, 1},
+		Buckets: []float64{0, 0.001, 0.01, math.Inf(1)},
+	}
+
+	if p := percentile(histogram, 0.5); p != 0.001 {
+		t.Errorf("p50 should be 0.001, was %f", p)
+	}
+	if p := percentile(histogram, 0.99); p != 0.01 {
+		t.Errorf("p99 should be 0.01, was %f", p)
+	}
+	// The last bucket is unbounded
+	if p := percentile(histogram, 1); p != 0.01 {
+		t.Errorf("p100 should be 0.01, was %f", p)
+	}
+	if p := percentile(&metrics.Float64Histogram{Counts: []uint64{0}, Buckets: []float64{0, 1}}, 0.99); p != 0 {
+		t.Errorf("p99 of an empty histogram should be 0, was %f", p)
+	}
+}
@@ -0,0 +1,121 @@
+package sqlsc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+	"github.com/hootsuite/healthchecks/internal/canary"
+)
+
+const (
+	// Default table of the canary rows
+	DefaultCanaryTable = "healthchecks_canary"
+	// Default timeout of the delete of a canary row, which does not share the timeout of the check
+	DefaultCanaryCleanupTimeout = 5 * time.Second
+)
+
+// SQLCanaryStatusChecker writes a uniquely keyed row, reads it back, verifies its value and deletes it again. Unlike
+// `SELECT 1` this catches a database that is up but read-only or otherwise rejecting writes.
+//
+// The table must exist and have `id` and `value` text columns, e.g.
+//
+//	CREATE TABLE healthchecks_canary (id VARCHAR(64) PRIMARY KEY, value VARCHAR(64) NOT NULL)
+//
+// The latency of each step is reported in the details. Any failed step results in a CRITICAL status and a round trip
+// slower than WarningLatency in a warning.
+type SQLCanaryStatusChecker struct {
+	DB                 *sql.DB
+	Table              string        // Optional canary table, defaults to `healthchecks_canary`
+	DollarPlaceholders bool          // Use `$1` style placeholders (Postgres) instead of `?`
+	WarningLatency     time.Duration // Optional round trip latency above which a warning is raised
+	Timeout            time.Duration // Optional timeout of the write and read steps
+	CleanupTimeout     time.Duration // Optional timeout of the delete step, defaults to 5 seconds
+	// Optional generator of the canary key and value, defaults to a random key and value
+	Values func() (string, string, error)
+}
+
+func (c SQLCanaryStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	ctx := context.Background()
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	table := c.Table
+	if table == "" {
+		table = DefaultCanaryTable
+	}
+
+	s := healthchecks.Status{
+		Description: name,
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	values := c.Values
+	if values == nil {
+		values = canaryValues
+	}
+
+	key, value, err := values()
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = fmt.Sprintf("%v check failed: %v", name, err)
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	// The delete step gets its own context, so the canary row is still cleaned up when the check timed out
+	cleanupTimeout := c.CleanupTimeout
+	if cleanupTimeout <= 0 {
+		cleanupTimeout = DefaultCanaryCleanupTimeout
+	}
+
+	total, details, err := canary.RoundTrip(
+		value,
+		func() error {
+			_, err := c.DB.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (id, value) VALUES (%s, %s)", table, c.placeholder(1), c.placeholder(2)), key, value)
+			return err
+		},
+		func() (string, error) {
+			var read string
+			err := c.DB.QueryRowContext(ctx, fmt.Sprintf("SELECT value FROM %s WHERE id = %s", table, c.placeholder(1)), key).Scan(&read)
+			return read, err
+		},
+		func() error {
+			cleanupCtx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+			defer cancel()
+			_, err := c.DB.ExecContext(cleanupCtx, fmt.Sprintf("DELETE FROM %s WHERE id = %s", table, c.placeholder(1)), key)
+			return err
+		},
+	)
+
+	switch {
+	case err != nil:
+		s.Result = healthchecks.CRITICAL
+		s.Details = fmt.Sprintf("%v check failed: %v; %s", name, err, details)
+	case c.WarningLatency > 0 && total > c.WarningLatency:
+		s.Result = healthchecks.WARNING
+		s.Details = fmt.Sprintf("Round trip of %s exceeds threshold of %s; %s", total, c.WarningLatency, details)
+	default:
+		s.Details = details
+	}
+
+	return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+}
+
+func (c SQLCanaryStatusChecker) placeholder(n int) string {
+	if c.DollarPlaceholders {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// Generate a random key and value for a canary row
+func canaryValues() (string, string, error) {
+	key, value, err := canary.Values()
+	return "canary-" + key, value, err
+}
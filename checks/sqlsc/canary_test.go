@@ -0,0 +1,122 @@
+package sqlsc
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hootsuite/healthchecks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanaryOK(t *testing.T) {
+	db, mock := newCanaryMock(t)
+	mock.ExpectExec("INSERT INTO healthchecks_canary (id, value) VALUES (?, ?)").
+		WithArgs("canary-key", "canary-value").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT value FROM healthchecks_canary WHERE id = ?").
+		WithArgs("canary-key").
+		WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("canary-value"))
+	mock.ExpectExec("DELETE FROM healthchecks_canary WHERE id = ?").
+		WithArgs("canary-key").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	checker := SQLCanaryStatusChecker{DB: db, Values: testCanaryValues, WarningLatency: time.Minute}
+	statusList := checker.CheckStatus("Canary").StatusList
+
+	assert.Len(t, statusList, 1)
+	assert.Equal(t, healthchecks.OK, statusList[0].Result)
+	assert.Regexp(t, `^write=\S+ read=\S+ delete=\S+$`, statusList[0].Details)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCanaryDollarPlaceholders(t *testing.T) {
+	db, mock := newCanaryMock(t)
+	mock.ExpectExec("INSERT INTO canary (id, value) VALUES ($1, $2)").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT value FROM canary WHERE id = $1").
+		WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("canary-value"))
+	mock.ExpectExec("DELETE FROM canary WHERE id = $1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	checker := SQLCanaryStatusChecker{DB: db, Values: testCanaryValues, Table: "canary", DollarPlaceholders: true}
+	status := checker.CheckStatus("Canary").StatusList[0]
+
+	assert.Equal(t, healthchecks.OK, status.Result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCanaryReadOnly(t *testing.T) {
+	db, mock := newCanaryMock(t)
+	mock.ExpectExec("INSERT INTO healthchecks_canary (id, value) VALUES (?, ?)").
+		WillReturnError(errors.New("cannot execute INSERT in a read-only transaction"))
+
+	checker := SQLCanaryStatusChecker{DB: db, Values: testCanaryValues}
+	status := checker.CheckStatus("Canary").StatusList[0]
+
+	assert.Equal(t, healthchecks.CRITICAL, status.Result)
+	assert.True(t, strings.HasPrefix(status.Details, "Canary check failed: write failed: cannot execute INSERT in a read-only transaction; write="), status.Details)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCanaryValueMismatchStillDeletes(t *testing.T) {
+	db, mock := newCanaryMock(t)
+	mock.ExpectExec("INSERT INTO healthchecks_canary (id, value) VALUES (?, ?)").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT value FROM healthchecks_canary WHERE id = ?").
+		WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("something-else"))
+	mock.ExpectExec("DELETE FROM healthchecks_canary WHERE id = ?").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	checker := SQLCanaryStatusChecker{DB: db, Values: testCanaryValues}
+	status := checker.CheckStatus("Canary").StatusList[0]
+
+	assert.Equal(t, healthchecks.CRITICAL, status.Result)
+	assert.Contains(t, status.Details, "read failed: expected value `canary-value`, got `something-else`")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCanarySlowRoundTrip(t *testing.T) {
+	db, mock := newCanaryMock(t)
+	mock.ExpectExec("INSERT INTO healthchecks_canary (id, value) VALUES (?, ?)").
+		WillDelayFor(10 * time.Millisecond).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT value FROM healthchecks_canary WHERE id = ?").
+		WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("canary-value"))
+	mock.ExpectExec("DELETE FROM healthchecks_canary WHERE id = ?").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	checker := SQLCanaryStatusChecker{DB: db, Values: testCanaryValues, WarningLatency: time.Millisecond}
+	status := checker.CheckStatus("Canary").StatusList[0]
+
+	assert.Equal(t, healthchecks.WARNING, status.Result)
+	assert.Contains(t, status.Details, "exceeds threshold of 1ms")
+}
+
+func TestCanaryCleanupAfterTimeout(t *testing.T) {
+	db, mock := newCanaryMock(t)
+	mock.ExpectExec("INSERT INTO healthchecks_canary (id, value) VALUES (?, ?)").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT value FROM healthchecks_canary WHERE id = ?").
+		WillDelayFor(50 * time.Millisecond).
+		WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("canary-value"))
+	mock.ExpectExec("DELETE FROM healthchecks_canary WHERE id = ?").
+		WithArgs("canary-key").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	checker := SQLCanaryStatusChecker{DB: db, Values: testCanaryValues, Timeout: 10 * time.Millisecond}
+	status := checker.CheckStatus("Canary").StatusList[0]
+
+	assert.Equal(t, healthchecks.CRITICAL, status.Result)
+	assert.Contains(t, status.Details, "read failed: ")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func testCanaryValues() (string, string, error) {
+	return "canary-key", "canary-value", nil
+}
+
+func newCanaryMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	return db, mock
+}
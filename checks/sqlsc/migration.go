@@ -0,0 +1,232 @@
+package sqlsc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// MigrationLayout is the layout of the table a migration tool records the applied migrations in
+type MigrationLayout string
+
+const (
+	// GolangMigrate is the `schema_migrations` layout of github.com/golang-migrate/migrate
+	GolangMigrate MigrationLayout = "golang-migrate"
+	// Goose is the `goose_db_version` layout of github.com/pressly/goose
+	Goose MigrationLayout = "goose"
+	// Flyway is the `flyway_schema_history` layout of Flyway
+	Flyway MigrationLayout = "flyway"
+)
+
+// MigrationStatusChecker compares the schema version of a database with the version the binary expects.
+//
+// The check is CRITICAL when the database is behind the expected version or no migration was applied, and WARN
+// when the database is ahead of the expected version or the last migration is dirty (failed halfway). A missing
+// ExpectedVersion is a configuration error reported as CRITICAL.
+type MigrationStatusChecker struct {
+	DB              *sql.DB
+	Layout          MigrationLayout
+	ExpectedVersion string        // The schema version the binary expects, e.g. `42` or `1.3.2` for Flyway
+	Table           string        // Optional migrations table, defaults to the table name used by the Layout
+	Timeout         time.Duration // Optional timeout of the check
+}
+
+type schemaVersion struct {
+	version string
+	dirty   bool
+}
+
+func (m MigrationStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	ctx := context.Background()
+	if m.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.Timeout)
+		defer cancel()
+	}
+
+	s := healthchecks.Status{
+		Description: name,
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	if strings.TrimSpace(m.ExpectedVersion) == "" {
+		s.Result = healthchecks.CRITICAL
+		s.Details = "MigrationStatusChecker requires an ExpectedVersion"
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	current, err := m.currentVersion(ctx)
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = fmt.Sprintf("%v check failed: %v", name, err)
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	if current == nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = fmt.Sprintf("No migrations applied, expecting schema version %s", m.ExpectedVersion)
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	versions := fmt.Sprintf("Schema version is %s, expecting %s", current.version, m.ExpectedVersion)
+	switch c := compareVersions(current.version, m.ExpectedVersion); {
+	case c < 0:
+		s.Result = healthchecks.CRITICAL
+		s.Details = fmt.Sprintf("%s, migrations are missing", versions)
+	case current.dirty:
+		s.Result = healthchecks.WARNING
+		s.Details = fmt.Sprintf("%s, last migration is dirty", versions)
+	case c > 0:
+		s.Result = healthchecks.WARNING
+		s.Details = fmt.Sprintf("%s, database is ahead of the service", versions)
+	}
+
+	return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+}
+
+// Read the current schema version, returns nil if no migration was applied
+func (m MigrationStatusChecker) currentVersion(ctx context.Context) (*schemaVersion, error) {
+	switch m.Layout {
+	case GolangMigrate:
+		return m.golangMigrateVersion(ctx)
+	case Goose:
+		return m.gooseVersion(ctx)
+	case Flyway:
+		return m.flywayVersion(ctx)
+	default:
+		return nil, fmt.Errorf("unknown migration layout `%s`", m.Layout)
+	}
+}
+
+func (m MigrationStatusChecker) table(defaultTable string) string {
+	if m.Table != "" {
+		return m.Table
+	}
+	return defaultTable
+}
+
+func (m MigrationStatusChecker) golangMigrateVersion(ctx context.Context) (*schemaVersion, error) {
+	var version int64
+	var dirty bool
+
+	query := fmt.Sprintf("SELECT version, dirty FROM %s LIMIT 1", m.table("schema_migrations"))
+	err := m.DB.QueryRowContext(ctx, query).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &schemaVersion{version: strconv.FormatInt(version, 10), dirty: dirty}, nil
+}
+
+// Goose records every migration and rollback, the current version is the most recent applied
+// version which has not been rolled back since
+func (m MigrationStatusChecker) gooseVersion(ctx context.Context) (*schemaVersion, error) {
+	query := fmt.Sprintf("SELECT version_id, is_applied FROM %s ORDER BY id DESC", m.table("goose_db_version"))
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rolledBack := map[int64]bool{}
+	for rows.Next() {
+		var version int64
+		var applied bool
+		if err := rows.Scan(&version, &applied); err != nil {
+			return nil, err
+		}
+
+		if !applied {
+			rolledBack[version] = true
+			continue
+		}
+
+		if !rolledBack[version] {
+			return &schemaVersion{version: strconv.FormatInt(version, 10)}, nil
+		}
+		delete(rolledBack, version)
+	}
+
+	return nil, rows.Err()
+}
+
+// Flyway records every versioned migration with its success, the current version is the highest
+// successful one and any failed migration makes the schema dirty
+func (m MigrationStatusChecker) flywayVersion(ctx context.Context) (*schemaVersion, error) {
+	query := fmt.Sprintf("SELECT version, success FROM %s WHERE version IS NOT NULL", m.table("flyway_schema_history"))
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var current *schemaVersion
+	dirty := false
+	for rows.Next() {
+		var version string
+		var success bool
+		if err := rows.Scan(&version, &success); err != nil {
+			return nil, err
+		}
+
+		if !success {
+			dirty = true
+			continue
+		}
+
+		if current == nil || compareVersions(version, current.version) > 0 {
+			current = &schemaVersion{version: version}
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if current != nil {
+		current.dirty = dirty
+	}
+
+	return current, nil
+}
+
+// Compare two dotted versions such as `1.10.2` part by part, numerically where both parts are numbers.
+// Underscores are treated as dots as in Flyway version names.
+func compareVersions(a string, b string) int {
+	aParts := strings.Split(strings.ReplaceAll(a, "_", "."), ".")
+	bParts := strings.Split(strings.ReplaceAll(b, "_", "."), ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		aPart, bPart := "0", "0"
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+
+		aNumber, aErr := strconv.ParseInt(aPart, 10, 64)
+		bNumber, bErr := strconv.ParseInt(bPart, 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNumber != bNumber {
+				if aNumber < bNumber {
+					return -1
+				}
+				return 1
+			}
+		case aPart != bPart:
+			return strings.Compare(aPart, bPart)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,144 @@
+package sqlsc
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hootsuite/healthchecks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationGolangMigrate(t *testing.T) {
+	tests := []struct {
+		version  int64
+		dirty    bool
+		expected healthchecks.Status
+	}{
+		{42, false, healthchecks.Status{Description: "Migrations", Result: healthchecks.OK, Details: ""}},
+		{41, false, healthchecks.Status{Description: "Migrations", Result: healthchecks.CRITICAL, Details: "Schema version is 41, expecting 42, migrations are missing"}},
+		{43, false, healthchecks.Status{Description: "Migrations", Result: healthchecks.WARNING, Details: "Schema version is 43, expecting 42, database is ahead of the service"}},
+		{42, true, healthchecks.Status{Description: "Migrations", Result: healthchecks.WARNING, Details: "Schema version is 42, expecting 42, last migration is dirty"}},
+		{41, true, healthchecks.Status{Description: "Migrations", Result: healthchecks.CRITICAL, Details: "Schema version is 41, expecting 42, migrations are missing"}},
+	}
+
+	for _, test := range tests {
+		db, mock := newMigrationMock(t)
+		mock.ExpectQuery("SELECT version, dirty FROM schema_migrations LIMIT 1").
+			WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(test.version, test.dirty))
+
+		checker := MigrationStatusChecker{DB: db, Layout: GolangMigrate, ExpectedVersion: "42"}
+		statusList := checker.CheckStatus("Migrations").StatusList
+
+		assert.Len(t, statusList, 1)
+		assert.Equal(t, test.expected, statusList[0])
+	}
+}
+
+func TestMigrationGolangMigrateNoRows(t *testing.T) {
+	db, mock := newMigrationMock(t)
+	mock.ExpectQuery("SELECT version, dirty FROM migrations LIMIT 1").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}))
+
+	checker := MigrationStatusChecker{DB: db, Layout: GolangMigrate, Table: "migrations", ExpectedVersion: "42"}
+	status := checker.CheckStatus("Migrations").StatusList[0]
+
+	assert.Equal(t, healthchecks.CRITICAL, status.Result)
+	assert.Equal(t, "No migrations applied, expecting schema version 42", status.Details)
+}
+
+func TestMigrationQueryError(t *testing.T) {
+	db, mock := newMigrationMock(t)
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations LIMIT 1").WillReturnError(sql.ErrConnDone)
+
+	checker := MigrationStatusChecker{DB: db, Layout: GolangMigrate, ExpectedVersion: "42"}
+	status := checker.CheckStatus("Migrations").StatusList[0]
+
+	assert.Equal(t, healthchecks.CRITICAL, status.Result)
+	assert.Equal(t, "Migrations check failed: sql: connection is already closed", status.Details)
+}
+
+func TestMigrationGoose(t *testing.T) {
+	db, mock := newMigrationMock(t)
+
+	// Version 3 was applied and then rolled back, so the current version is 2
+	mock.ExpectQuery("SELECT version_id, is_applied FROM goose_db_version ORDER BY id DESC").
+		WillReturnRows(sqlmock.NewRows([]string{"version_id", "is_applied"}).
+			AddRow(3, false).
+			AddRow(3, true).
+			AddRow(2, true).
+			AddRow(1, true).
+			AddRow(0, true))
+
+	checker := MigrationStatusChecker{DB: db, Layout: Goose, ExpectedVersion: "3"}
+	status := checker.CheckStatus("Migrations").StatusList[0]
+
+	assert.Equal(t, healthchecks.CRITICAL, status.Result)
+	assert.Equal(t, "Schema version is 2, expecting 3, migrations are missing", status.Details)
+}
+
+func TestMigrationFlyway(t *testing.T) {
+	db, mock := newMigrationMock(t)
+	mock.ExpectQuery("SELECT version, success FROM flyway_schema_history WHERE version IS NOT NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "success"}).
+			AddRow("1", true).
+			AddRow("1.2", true).
+			AddRow("1.10", true))
+
+	checker := MigrationStatusChecker{DB: db, Layout: Flyway, ExpectedVersion: "1.10"}
+	status := checker.CheckStatus("Migrations").StatusList[0]
+
+	assert.Equal(t, healthchecks.OK, status.Result)
+}
+
+func TestMigrationFlywayFailed(t *testing.T) {
+	db, mock := newMigrationMock(t)
+	mock.ExpectQuery("SELECT version, success FROM flyway_schema_history WHERE version IS NOT NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "success"}).
+			AddRow("1", true).
+			AddRow("2", false))
+
+	checker := MigrationStatusChecker{DB: db, Layout: Flyway, ExpectedVersion: "1"}
+	status := checker.CheckStatus("Migrations").StatusList[0]
+
+	assert.Equal(t, healthchecks.WARNING, status.Result)
+	assert.Equal(t, "Schema version is 1, expecting 1, last migration is dirty", status.Details)
+}
+
+func TestMigrationUnknownLayout(t *testing.T) {
+	db, _ := newMigrationMock(t)
+
+	checker := MigrationStatusChecker{DB: db, Layout: "liquibase", ExpectedVersion: "1"}
+	status := checker.CheckStatus("Migrations").StatusList[0]
+
+	assert.Equal(t, healthchecks.CRITICAL, status.Result)
+	assert.Equal(t, "Migrations check failed: unknown migration layout `liquibase`", status.Details)
+}
+
+func TestMigrationNoExpectedVersion(t *testing.T) {
+	db, mock := newMigrationMock(t)
+
+	checker := MigrationStatusChecker{DB: db, Layout: GolangMigrate}
+	status := checker.CheckStatus("Migrations").StatusList[0]
+
+	assert.Equal(t, healthchecks.CRITICAL, status.Result)
+	assert.Equal(t, "MigrationStatusChecker requires an ExpectedVersion", status.Details)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("42", "42"))
+	assert.Equal(t, -1, compareVersions("1.2", "1.10"))
+	assert.Equal(t, 1, compareVersions("2", "1.9.9"))
+	assert.Equal(t, 0, compareVersions("1.0", "1"))
+	assert.Equal(t, 0, compareVersions("1_2", "1.2"))
+	assert.Equal(t, -1, compareVersions("20230101120000", "20230102090000"))
+}
+
+func newMigrationMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	return db, mock
+}
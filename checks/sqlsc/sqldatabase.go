@@ -1,32 +1,165 @@
 package sqlsc
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/hootsuite/healthchecks"
+	"strings"
+	"sync"
+	"time"
 )
 
 type SQLDBStatusChecker struct {
-	DB *sql.DB
+	DB             *sql.DB
+	Timeout        time.Duration   // Optional timeout of the whole check
+	ProbeQuery     string          // Optional query to run instead of `SELECT 1`
+	ExpectedResult *string         // Optional value the first column of the first row returned by ProbeQuery must have
+	PoolThresholds *PoolThresholds // Optional connection pool thresholds, pool statistics are reported in the details when set
+}
+
+// PoolThresholds are the connection pool thresholds of a SQLDBStatusChecker. A threshold is disabled when 0.
+//
+// The wait thresholds apply to the growth of the pool's wait count and wait duration since the previous check,
+// which is tracked in the PoolThresholds. A PoolThresholds should therefore not be shared between checkers.
+type PoolThresholds struct {
+	WarningInUseRatio    float64       // Ratio of in use to max open connections, only evaluated if the pool has a limit
+	CriticalInUseRatio   float64       // Ratio of in use to max open connections, only evaluated if the pool has a limit
+	WarningWaitCount     int64         // Number of new waits for a connection since the previous check
+	CriticalWaitCount    int64         // Number of new waits for a connection since the previous check
+	WarningWaitDuration  time.Duration // Time spent waiting for a connection since the previous check
+	CriticalWaitDuration time.Duration // Time spent waiting for a connection since the previous check
+
+	mu        sync.Mutex
+	lastStats *sql.DBStats
 }
 
 func (d SQLDBStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	ctx := context.Background()
+	if d.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.Timeout)
+		defer cancel()
+	}
+
+	// Evaluate the pool before running any query, an exhausted pool would otherwise show up as a timeout only
+	poolResult := healthchecks.OK
+	poolDetails := ""
+	if d.PoolThresholds != nil {
+		poolResult, poolDetails = d.PoolThresholds.evaluate(d.DB.Stats())
+	}
 
-	_, err := d.DB.Exec("SELECT 1")
+	err := d.probe(ctx)
 
 	var result healthchecks.Status
 	if err != nil {
 		result = healthchecks.Status{
 			Description: name,
 			Result:      healthchecks.CRITICAL,
-			Details:     fmt.Sprintf("%v check failed: %v", name, err),
+			Details:     joinDetails(fmt.Sprintf("%v check failed: %v", name, err), poolDetails),
 		}
 	} else {
 		result = healthchecks.Status{
 			Description: name,
-			Result:      healthchecks.OK,
-			Details:     "",
+			Result:      poolResult,
+			Details:     poolDetails,
 		}
 	}
 	return healthchecks.StatusList{StatusList: []healthchecks.Status{result}}
 }
+
+func (d SQLDBStatusChecker) probe(ctx context.Context) error {
+	if err := d.DB.PingContext(ctx); err != nil {
+		return err
+	}
+
+	if d.ProbeQuery == "" {
+		_, err := d.DB.ExecContext(ctx, "SELECT 1")
+		return err
+	}
+
+	if d.ExpectedResult == nil {
+		_, err := d.DB.ExecContext(ctx, d.ProbeQuery)
+		return err
+	}
+
+	var value sql.NullString
+	if err := d.DB.QueryRowContext(ctx, d.ProbeQuery).Scan(&value); err != nil {
+		return err
+	}
+
+	if !value.Valid || value.String != *d.ExpectedResult {
+		return fmt.Errorf("expected probe query result `%s`, got `%s`", *d.ExpectedResult, value.String)
+	}
+
+	return nil
+}
+
+// Evaluate the pool statistics against the thresholds and return the alert level and details
+func (p *PoolThresholds) evaluate(stats sql.DBStats) (healthchecks.AlertLevel, string) {
+	p.mu.Lock()
+	waitCount := stats.WaitCount
+	waitDuration := stats.WaitDuration
+	if p.lastStats != nil {
+		waitCount -= p.lastStats.WaitCount
+		waitDuration -= p.lastStats.WaitDuration
+	}
+	p.lastStats = &stats
+	p.mu.Unlock()
+
+	alertLevel := healthchecks.OK
+	reasons := []string{}
+	raise := func(level healthchecks.AlertLevel, reason string) {
+		alertLevel = healthchecks.Worst(alertLevel, level)
+		reasons = append(reasons, reason)
+	}
+
+	if stats.MaxOpenConnections > 0 {
+		ratio := float64(stats.InUse) / float64(stats.MaxOpenConnections)
+		inUse := fmt.Sprintf("%d of %d connections in use", stats.InUse, stats.MaxOpenConnections)
+		if p.CriticalInUseRatio > 0 && ratio >= p.CriticalInUseRatio {
+			raise(healthchecks.CRITICAL, fmt.Sprintf("%s exceeds critical threshold of %.0f%%", inUse, p.CriticalInUseRatio*100))
+		} else if p.WarningInUseRatio > 0 && ratio >= p.WarningInUseRatio {
+			raise(healthchecks.WARNING, fmt.Sprintf("%s exceeds warning threshold of %.0f%%", inUse, p.WarningInUseRatio*100))
+		}
+	}
+
+	waits := fmt.Sprintf("%d new waits for a connection", waitCount)
+	if p.CriticalWaitCount > 0 && waitCount >= p.CriticalWaitCount {
+		raise(healthchecks.CRITICAL, fmt.Sprintf("%s exceeds critical threshold of %d", waits, p.CriticalWaitCount))
+	} else if p.WarningWaitCount > 0 && waitCount >= p.WarningWaitCount {
+		raise(healthchecks.WARNING, fmt.Sprintf("%s exceeds warning threshold of %d", waits, p.WarningWaitCount))
+	}
+
+	waited := fmt.Sprintf("%s spent waiting for a connection", waitDuration)
+	if p.CriticalWaitDuration > 0 && waitDuration >= p.CriticalWaitDuration {
+		raise(healthchecks.CRITICAL, fmt.Sprintf("%s exceeds critical threshold of %s", waited, p.CriticalWaitDuration))
+	} else if p.WarningWaitDuration > 0 && waitDuration >= p.WarningWaitDuration {
+		raise(healthchecks.WARNING, fmt.Sprintf("%s exceeds warning threshold of %s", waited, p.WarningWaitDuration))
+	}
+
+	reasons = append(reasons, formatPoolStats(stats))
+	return alertLevel, strings.Join(reasons, "; ")
+}
+
+func formatPoolStats(stats sql.DBStats) string {
+	return fmt.Sprintf(
+		"pool: open=%d in_use=%d idle=%d max_open=%d wait_count=%d wait_duration=%s",
+		stats.OpenConnections,
+		stats.InUse,
+		stats.Idle,
+		stats.MaxOpenConnections,
+		stats.WaitCount,
+		stats.WaitDuration,
+	)
+}
+
+func joinDetails(details ...string) string {
+	nonEmpty := []string{}
+	for _, d := range details {
+		if d != "" {
+			nonEmpty = append(nonEmpty, d)
+		}
+	}
+	return strings.Join(nonEmpty, "; ")
+}
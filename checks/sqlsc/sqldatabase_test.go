@@ -1,12 +1,15 @@
 package sqlsc
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/hootsuite/healthchecks"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestDatabaseOK(t *testing.T) {
@@ -45,6 +48,132 @@ func TestDatabaseCRIT(t *testing.T) {
 	assert.Equal(t, expectedCRITResult, status)
 }
 
+func TestDatabasePingCRIT(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mockedPingError := errors.New("Mocked ping error occurred.")
+	mock.ExpectPing().WillReturnError(mockedPingError)
+
+	expectedCRITResult := createExpectedCRITResponse(mockedPingError)
+
+	checker := SQLDBStatusChecker{DB: db}
+	statusList := checker.CheckStatus(expectedCRITResult.Description).StatusList
+
+	assert.Equal(t, expectedCRITResult, statusList[0])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDatabaseProbeQueryOK(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectQuery("SELECT pg_is_in_recovery()").WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow("false"))
+
+	expected := "false"
+	checker := SQLDBStatusChecker{DB: db, ProbeQuery: "SELECT pg_is_in_recovery()", ExpectedResult: &expected}
+	statusList := checker.CheckStatus(expectedOKResponse.Description).StatusList
+
+	assert.Equal(t, expectedOKResponse, statusList[0])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDatabaseProbeQueryUnexpectedResult(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectQuery("SELECT pg_is_in_recovery()").WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow("true"))
+
+	expected := "false"
+	checker := SQLDBStatusChecker{DB: db, ProbeQuery: "SELECT pg_is_in_recovery()", ExpectedResult: &expected}
+	statusList := checker.CheckStatus(expectedOKResponse.Description).StatusList
+
+	assert.Equal(t, createExpectedCRITResponse(errors.New("expected probe query result `false`, got `true`")), statusList[0])
+}
+
+func TestDatabaseProbeQueryExec(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectExec("SELECT 1 FROM users LIMIT 1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	checker := SQLDBStatusChecker{DB: db, ProbeQuery: "SELECT 1 FROM users LIMIT 1"}
+	statusList := checker.CheckStatus(expectedOKResponse.Description).StatusList
+
+	assert.Equal(t, expectedOKResponse, statusList[0])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDatabasePoolInUseWARN(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db.SetMaxOpenConns(2)
+
+	// Hold a connection so half of the pool is in use
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when getting a connection", err)
+	}
+	defer conn.Close()
+
+	mock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	checker := SQLDBStatusChecker{DB: db, PoolThresholds: &PoolThresholds{WarningInUseRatio: 0.5, CriticalInUseRatio: 0.9}}
+	status := checker.CheckStatus(expectedOKResponse.Description).StatusList[0]
+
+	assert.Equal(t, healthchecks.WARNING, status.Result)
+	assert.Equal(t, "1 of 2 connections in use exceeds warning threshold of 50%; pool: open=1 in_use=1 idle=0 max_open=2 wait_count=0 wait_duration=0s", status.Details)
+}
+
+func TestDatabasePoolStatsOK(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	checker := SQLDBStatusChecker{DB: db, PoolThresholds: &PoolThresholds{WarningInUseRatio: 0.5}}
+	status := checker.CheckStatus(expectedOKResponse.Description).StatusList[0]
+
+	assert.Equal(t, healthchecks.OK, status.Result)
+	assert.Equal(t, "pool: open=1 in_use=0 idle=1 max_open=0 wait_count=0 wait_duration=0s", status.Details)
+}
+
+func TestPoolThresholdsWaitGrowth(t *testing.T) {
+	thresholds := &PoolThresholds{
+		WarningWaitCount:     10,
+		CriticalWaitCount:    100,
+		WarningWaitDuration:  time.Second,
+		CriticalWaitDuration: time.Minute,
+	}
+
+	result, _ := thresholds.evaluate(sql.DBStats{WaitCount: 5, WaitDuration: 500 * time.Millisecond})
+	assert.Equal(t, healthchecks.OK, result)
+
+	result, details := thresholds.evaluate(sql.DBStats{WaitCount: 20, WaitDuration: 2 * time.Second})
+	assert.Equal(t, healthchecks.WARNING, result)
+	assert.Contains(t, details, "15 new waits for a connection exceeds warning threshold of 10")
+	assert.Contains(t, details, "1.5s spent waiting for a connection exceeds warning threshold of 1s")
+
+	result, details = thresholds.evaluate(sql.DBStats{WaitCount: 200, WaitDuration: 3 * time.Second})
+	assert.Equal(t, healthchecks.CRITICAL, result)
+	assert.Contains(t, details, "180 new waits for a connection exceeds critical threshold of 100")
+
+	result, _ = thresholds.evaluate(sql.DBStats{WaitCount: 200, WaitDuration: 3 * time.Second})
+	assert.Equal(t, healthchecks.OK, result)
+}
+
 var expectedOKResponse = healthchecks.Status{
 	Description: "Mysql Test Database",
 	Result:      healthchecks.OK,
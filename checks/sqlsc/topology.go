@@ -0,0 +1,218 @@
+package sqlsc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// ReplicaLagQuerier reads the replication lag of a replica. Implementations are dialect specific.
+type ReplicaLagQuerier interface {
+	ReplicaLag(ctx context.Context, db *sql.DB) (time.Duration, error)
+}
+
+// PostgresReplicaLag reads the replication lag of a Postgres streaming replica. The lag is 0 when the replica
+// has replayed everything it received, so an idle primary does not show up as lag.
+type PostgresReplicaLag struct{}
+
+func (PostgresReplicaLag) ReplicaLag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	var seconds sql.NullFloat64
+	err := db.QueryRowContext(ctx, `SELECT CASE
+		WHEN NOT pg_is_in_recovery() THEN NULL
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`).Scan(&seconds)
+	if err != nil {
+		return 0, err
+	}
+
+	if !seconds.Valid {
+		return 0, errors.New("node is not a replica")
+	}
+
+	return time.Duration(seconds.Float64 * float64(time.Second)), nil
+}
+
+// MySQLReplicaLag reads the replication lag of a MySQL replica from `Seconds_Behind_Source`
+// (or `Seconds_Behind_Master` on older versions).
+type MySQLReplicaLag struct {
+	Query string // Optional query, defaults to `SHOW REPLICA STATUS`, use `SHOW SLAVE STATUS` before MySQL 8.0.22
+}
+
+func (m MySQLReplicaLag) ReplicaLag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	query := m.Query
+	if query == "" {
+		query = "SHOW REPLICA STATUS"
+	}
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+		return 0, errors.New("node is not a replica")
+	}
+
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return 0, err
+	}
+
+	for i, column := range columns {
+		if column != "Seconds_Behind_Source" && column != "Seconds_Behind_Master" {
+			continue
+		}
+
+		if values[i] == nil {
+			return 0, errors.New("replication is not running")
+		}
+
+		seconds, err := strconv.ParseInt(string(values[i]), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s value `%s`", column, values[i])
+		}
+
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	return 0, errors.New("replica status has no Seconds_Behind_Source column")
+}
+
+// DBNode is a single database of a topology
+type DBNode struct {
+	Name string
+	DB   *sql.DB
+}
+
+// TopologyStatusChecker checks a primary database and its read replicas.
+//
+// The returned StatusList starts with the combined status of the topology followed by one entry per node, primary first.
+// The topology is CRITICAL if the primary is down or at least half of the replicas are down, and WARN if a minority
+// of the replicas are down or a replica is lagging.
+type TopologyStatusChecker struct {
+	Primary     DBNode
+	Replicas    []DBNode
+	LagQuerier  ReplicaLagQuerier // Optional querier for the replication lag of the replicas, lag is not checked if nil
+	WarningLag  time.Duration     // Optional replication lag above which a replica is lagging with a warning
+	CriticalLag time.Duration     // Optional replication lag above which a replica is lagging with a critical alert
+	Timeout     time.Duration     // Optional timeout of the check of each node
+}
+
+func (t TopologyStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	nodes := make([]healthchecks.Status, len(t.Replicas)+1)
+	lagging := make([]bool, len(t.Replicas)+1)
+
+	var wg sync.WaitGroup
+	wg.Add(len(nodes))
+
+	go func() {
+		defer wg.Done()
+		nodes[0], _ = t.checkNode(t.Primary, false)
+	}()
+
+	for i, replica := range t.Replicas {
+		go func(i int, replica DBNode) {
+			defer wg.Done()
+			nodes[i+1], lagging[i+1] = t.checkNode(replica, true)
+		}(i, replica)
+	}
+
+	wg.Wait()
+
+	replicasDown := 0
+	replicasLagging := 0
+	for i, node := range nodes[1:] {
+		if lagging[i+1] {
+			replicasLagging++
+		} else if node.Result != healthchecks.OK {
+			replicasDown++
+		}
+	}
+
+	primaryDetails := "primary is up"
+	if nodes[0].Result != healthchecks.OK {
+		primaryDetails = "primary is down"
+	}
+	details := fmt.Sprintf(
+		"%s, %d of %d replicas up, %d lagging",
+		primaryDetails,
+		len(t.Replicas)-replicasDown,
+		len(t.Replicas),
+		replicasLagging,
+	)
+
+	result := healthchecks.OK
+	switch {
+	case nodes[0].Result != healthchecks.OK:
+		result = healthchecks.CRITICAL
+	case replicasDown > 0 && replicasDown*2 >= len(t.Replicas):
+		result = healthchecks.CRITICAL
+	case replicasDown > 0 || replicasLagging > 0:
+		result = healthchecks.WARNING
+	}
+
+	return healthchecks.StatusList{
+		StatusList: append([]healthchecks.Status{
+			{
+				Description: name,
+				Result:      result,
+				Details:     details,
+			},
+		}, nodes...),
+	}
+}
+
+// Check a single node, returns whether the node is up but its replication lag exceeds a threshold
+func (t TopologyStatusChecker) checkNode(node DBNode, replica bool) (healthchecks.Status, bool) {
+	s := SQLDBStatusChecker{DB: node.DB, Timeout: t.Timeout}.CheckStatus(node.Name).StatusList[0]
+	if s.Result != healthchecks.OK || !replica || t.LagQuerier == nil {
+		return s, false
+	}
+
+	ctx := context.Background()
+	if t.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.Timeout)
+		defer cancel()
+	}
+
+	lag, err := t.LagQuerier.ReplicaLag(ctx, node.DB)
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = fmt.Sprintf("%v replication check failed: %v", node.Name, err)
+		return s, false
+	}
+
+	lagDetails := fmt.Sprintf("Replication lag of %s", lag)
+	if t.CriticalLag > 0 && lag > t.CriticalLag {
+		s.Result = healthchecks.CRITICAL
+		s.Details = fmt.Sprintf("%s exceeds threshold of %s", lagDetails, t.CriticalLag)
+	} else if t.WarningLag > 0 && lag > t.WarningLag {
+		s.Result = healthchecks.WARNING
+		s.Details = fmt.Sprintf("%s exceeds threshold of %s", lagDetails, t.WarningLag)
+	} else {
+		s.Details = lagDetails
+	}
+
+	return s, s.Result != healthchecks.OK
+}
@@ -0,0 +1,152 @@
+package sqlsc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hootsuite/healthchecks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopologyOK(t *testing.T) {
+	primary := newNode(t, "primary", nil)
+	replica1 := newNode(t, "replica-1", nil)
+	replica2 := newNode(t, "replica-2", nil)
+
+	checker := TopologyStatusChecker{
+		Primary:    primary,
+		Replicas:   []DBNode{replica1, replica2},
+		LagQuerier: MockLagQuerier{replica1.DB: time.Second, replica2.DB: 2 * time.Second},
+		WarningLag: 10 * time.Second,
+	}
+	statusList := checker.CheckStatus("Topology").StatusList
+
+	assert.Equal(t, []healthchecks.Status{
+		{Description: "Topology", Result: healthchecks.OK, Details: "primary is up, 2 of 2 replicas up, 0 lagging"},
+		{Description: "primary", Result: healthchecks.OK, Details: ""},
+		{Description: "replica-1", Result: healthchecks.OK, Details: "Replication lag of 1s"},
+		{Description: "replica-2", Result: healthchecks.OK, Details: "Replication lag of 2s"},
+	}, statusList)
+}
+
+func TestTopologyPrimaryDown(t *testing.T) {
+	primary := newNode(t, "primary", errors.New("connection refused"))
+	replica := newNode(t, "replica-1", nil)
+
+	checker := TopologyStatusChecker{Primary: primary, Replicas: []DBNode{replica}}
+	statusList := checker.CheckStatus("Topology").StatusList
+
+	assert.Len(t, statusList, 3)
+	assert.Equal(t, healthchecks.Status{Description: "Topology", Result: healthchecks.CRITICAL, Details: "primary is down, 1 of 1 replicas up, 0 lagging"}, statusList[0])
+	assert.Equal(t, healthchecks.Status{Description: "primary", Result: healthchecks.CRITICAL, Details: "primary check failed: connection refused"}, statusList[1])
+}
+
+func TestTopologyReplicaLagging(t *testing.T) {
+	primary := newNode(t, "primary", nil)
+	replica1 := newNode(t, "replica-1", nil)
+	replica2 := newNode(t, "replica-2", nil)
+
+	checker := TopologyStatusChecker{
+		Primary:     primary,
+		Replicas:    []DBNode{replica1, replica2},
+		LagQuerier:  MockLagQuerier{replica1.DB: 20 * time.Second, replica2.DB: 2 * time.Minute},
+		WarningLag:  10 * time.Second,
+		CriticalLag: time.Minute,
+	}
+	statusList := checker.CheckStatus("Topology").StatusList
+
+	assert.Equal(t, healthchecks.Status{Description: "Topology", Result: healthchecks.WARNING, Details: "primary is up, 2 of 2 replicas up, 2 lagging"}, statusList[0])
+	assert.Equal(t, healthchecks.Status{Description: "replica-1", Result: healthchecks.WARNING, Details: "Replication lag of 20s exceeds threshold of 10s"}, statusList[2])
+	assert.Equal(t, healthchecks.Status{Description: "replica-2", Result: healthchecks.CRITICAL, Details: "Replication lag of 2m0s exceeds threshold of 1m0s"}, statusList[3])
+}
+
+func TestTopologyMinorityOfReplicasDown(t *testing.T) {
+	primary := newNode(t, "primary", nil)
+	replica1 := newNode(t, "replica-1", errors.New("connection refused"))
+	replica2 := newNode(t, "replica-2", nil)
+	replica3 := newNode(t, "replica-3", nil)
+
+	checker := TopologyStatusChecker{Primary: primary, Replicas: []DBNode{replica1, replica2, replica3}}
+	statusList := checker.CheckStatus("Topology").StatusList
+
+	assert.Len(t, statusList, 5)
+	assert.Equal(t, healthchecks.Status{Description: "Topology", Result: healthchecks.WARNING, Details: "primary is up, 2 of 3 replicas up, 0 lagging"}, statusList[0])
+}
+
+func TestTopologyHalfOfReplicasDown(t *testing.T) {
+	primary := newNode(t, "primary", nil)
+	replica1 := newNode(t, "replica-1", errors.New("connection refused"))
+	replica2 := newNode(t, "replica-2", nil)
+
+	checker := TopologyStatusChecker{Primary: primary, Replicas: []DBNode{replica1, replica2}}
+	statusList := checker.CheckStatus("Topology").StatusList
+
+	assert.Equal(t, healthchecks.Status{Description: "Topology", Result: healthchecks.CRITICAL, Details: "primary is up, 1 of 2 replicas up, 0 lagging"}, statusList[0])
+}
+
+func TestPostgresReplicaLag(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectQuery("SELECT CASE").WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(1.5))
+	mock.ExpectQuery("SELECT CASE").WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(nil))
+
+	lag, err := PostgresReplicaLag{}.ReplicaLag(context.Background(), db)
+	assert.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, lag)
+
+	_, err = PostgresReplicaLag{}.ReplicaLag(context.Background(), db)
+	assert.EqualError(t, err, "node is not a replica")
+}
+
+func TestMySQLReplicaLag(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectQuery("SHOW REPLICA STATUS").
+		WillReturnRows(sqlmock.NewRows([]string{"Replica_IO_State", "Seconds_Behind_Source"}).AddRow("Waiting for source", "7"))
+	mock.ExpectQuery("SHOW REPLICA STATUS").
+		WillReturnRows(sqlmock.NewRows([]string{"Replica_IO_State", "Seconds_Behind_Source"}).AddRow("", nil))
+	mock.ExpectQuery("SHOW SLAVE STATUS").
+		WillReturnRows(sqlmock.NewRows([]string{"Slave_IO_State", "Seconds_Behind_Master"}))
+
+	lag, err := MySQLReplicaLag{}.ReplicaLag(context.Background(), db)
+	assert.NoError(t, err)
+	assert.Equal(t, 7*time.Second, lag)
+
+	_, err = MySQLReplicaLag{}.ReplicaLag(context.Background(), db)
+	assert.EqualError(t, err, "replication is not running")
+
+	_, err = MySQLReplicaLag{Query: "SHOW SLAVE STATUS"}.ReplicaLag(context.Background(), db)
+	assert.EqualError(t, err, "node is not a replica")
+}
+
+// Mocks
+type MockLagQuerier map[*sql.DB]time.Duration
+
+func (m MockLagQuerier) ReplicaLag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	return m[db], nil
+}
+
+func newNode(t *testing.T, name string, err error) DBNode {
+	db, mock, mockErr := sqlmock.New()
+	if mockErr != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", mockErr)
+	}
+
+	if err != nil {
+		mock.ExpectExec("SELECT 1").WillReturnError(err)
+	} else {
+		mock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	return DBNode{Name: name, DB: db}
+}
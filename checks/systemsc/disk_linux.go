@@ -0,0 +1,99 @@
+package systemsc
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+
+	"github.com/hootsuite/healthchecks"
+	"github.com/hootsuite/healthchecks/internal/units"
+)
+
+// DiskStatusChecker checks the disk space and inodes used on mount points. The returned StatusList starts with the
+// worst status of the mount points, followed by one entry per mount point.
+//
+// The used ratio is computed as df does, against the space available to unprivileged users. A threshold is disabled
+// when 0.
+type DiskStatusChecker struct {
+	MountPoints             []string // The mount points to check, any path on the filesystem works
+	WarningUsedRatio        float64  // Ratio of used disk space
+	CriticalUsedRatio       float64  // Ratio of used disk space
+	WarningInodesUsedRatio  float64  // Ratio of used inodes
+	CriticalInodesUsedRatio float64  // Ratio of used inodes
+}
+
+func (d DiskStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	if len(d.MountPoints) == 0 {
+		return healthchecks.StatusList{
+			StatusList: []healthchecks.Status{
+				{
+					Description: name,
+					Result:      healthchecks.CRITICAL,
+					Details:     "DiskStatusChecker requires at least one mount point",
+				},
+			},
+		}
+	}
+
+	a := newAlerts()
+	statuses := make([]healthchecks.Status, 0, len(d.MountPoints))
+	for _, mountPoint := range d.MountPoints {
+		s := d.checkMountPoint(name, mountPoint)
+		if s.Result != healthchecks.OK {
+			a.raise(s.Result, mountPoint)
+		}
+		statuses = append(statuses, s)
+	}
+
+	s := healthchecks.Status{
+		Description: name,
+		Result:      a.result,
+		Details:     fmt.Sprintf("%d of %d mount points are OK", len(d.MountPoints)-len(a.reasons), len(d.MountPoints)),
+	}
+	if len(a.reasons) > 0 {
+		s.Details = fmt.Sprintf("%s; failing: %s", s.Details, strings.Join(a.reasons, ", "))
+	}
+
+	return healthchecks.StatusList{StatusList: append([]healthchecks.Status{s}, statuses...)}
+}
+
+func (d DiskStatusChecker) checkMountPoint(name string, mountPoint string) healthchecks.Status {
+	s := healthchecks.Status{
+		Description: fmt.Sprintf("%s (%s)", name, mountPoint),
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(mountPoint, &stat); err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = fmt.Sprintf("Error reading filesystem stats of %s: %s", mountPoint, err.Error())
+		return s
+	}
+
+	blockSize := uint64(stat.Bsize)
+	used := (stat.Blocks - stat.Bfree) * blockSize
+	available := stat.Bavail * blockSize
+	usedRatio := ratio(used, used+available)
+
+	inodesUsed := stat.Files - stat.Ffree
+	inodesUsedRatio := ratio(inodesUsed, stat.Files)
+
+	a := newAlerts()
+	a.ratio("Disk usage", usedRatio, d.WarningUsedRatio, d.CriticalUsedRatio)
+	a.ratio("Inodes usage", inodesUsedRatio, d.WarningInodesUsedRatio, d.CriticalInodesUsedRatio)
+
+	a.reasons = append(a.reasons, fmt.Sprintf(
+		"used=%s available=%s (%.0f%%) inodes_used=%d inodes=%d (%.0f%%)",
+		units.FormatBytes(used),
+		units.FormatBytes(available),
+		usedRatio*100,
+		inodesUsed,
+		stat.Files,
+		inodesUsedRatio*100,
+	))
+
+	s.Result = a.result
+	s.Details = strings.Join(a.reasons, "; ")
+	return s
+}
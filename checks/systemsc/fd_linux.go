@@ -0,0 +1,100 @@
+package systemsc
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// FileDescriptorStatusChecker checks the number of file descriptors open by the process against its RLIMIT_NOFILE
+// soft limit. A threshold is disabled when 0.
+type FileDescriptorStatusChecker struct {
+	WarningUsedRatio  float64 // Ratio of the open file descriptors limit used
+	CriticalUsedRatio float64 // Ratio of the open file descriptors limit used
+	ProcRoot          string  // Optional root of the proc filesystem, defaults to DefaultProcRoot
+}
+
+func (f FileDescriptorStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	s := healthchecks.Status{
+		Description: name,
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	procRoot := f.ProcRoot
+	if procRoot == "" {
+		procRoot = DefaultProcRoot
+	}
+
+	fds, err := ioutil.ReadDir(filepath.Join(procRoot, "self", "fd"))
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = err.Error()
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+	open := uint64(len(fds))
+
+	limit, limited, err := readOpenFilesLimit(procRoot)
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = err.Error()
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	if !limited {
+		s.Details = fmt.Sprintf("open=%d limit=unlimited", open)
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	usedRatio := ratio(open, limit)
+	a := newAlerts()
+	a.ratio("File descriptors usage", usedRatio, f.WarningUsedRatio, f.CriticalUsedRatio)
+	a.reasons = append(a.reasons, fmt.Sprintf("open=%d limit=%d (%.0f%%)", open, limit, usedRatio*100))
+
+	s.Result = a.result
+	s.Details = strings.Join(a.reasons, "; ")
+	return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+}
+
+// Read the RLIMIT_NOFILE soft limit of the process from `limits`, returning false if unlimited
+func readOpenFilesLimit(procRoot string) (uint64, bool, error) {
+	path := filepath.Join(procRoot, "self", "limits")
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, false, err
+	}
+	defer file.Close()
+
+	// Max open files            1024                 4096                 files
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "Max open files") {
+			continue
+		}
+
+		fields := strings.Fields(strings.TrimPrefix(line, "Max open files"))
+		if len(fields) < 1 {
+			break
+		}
+		if fields[0] == "unlimited" {
+			return 0, false, nil
+		}
+		limit, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("Error parsing open files limit in %s: %s", path, err.Error())
+		}
+		return limit, true, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, false, err
+	}
+
+	return 0, false, fmt.Errorf("Open files limit not found in %s", path)
+}
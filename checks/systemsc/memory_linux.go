@@ -0,0 +1,145 @@
+package systemsc
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hootsuite/healthchecks"
+	"github.com/hootsuite/healthchecks/internal/units"
+)
+
+// Memory limits of cgroup v1 above this value mean no limit is set
+const cgroupV1Unlimited = uint64(1) << 62
+
+// MemoryStatusChecker checks the resident set size of the process against the memory limit of its cgroup, reading
+// the cgroup v2 `memory.max` or cgroup v1 `memory.limit_in_bytes`. The status is OK if no limit is set. A threshold is
+// disabled when 0.
+type MemoryStatusChecker struct {
+	WarningUsedRatio  float64 // Ratio of the cgroup memory limit used by the process
+	CriticalUsedRatio float64 // Ratio of the cgroup memory limit used by the process
+	ProcRoot          string  // Optional root of the proc filesystem, defaults to DefaultProcRoot
+	CgroupRoot        string  // Optional root of the cgroup filesystem, defaults to DefaultCgroupRoot
+}
+
+func (m MemoryStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	s := healthchecks.Status{
+		Description: name,
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	procRoot := m.ProcRoot
+	if procRoot == "" {
+		procRoot = DefaultProcRoot
+	}
+	cgroupRoot := m.CgroupRoot
+	if cgroupRoot == "" {
+		cgroupRoot = DefaultCgroupRoot
+	}
+
+	rss, err := readRSS(procRoot)
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = err.Error()
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	limit, limited, err := readMemoryLimit(procRoot, cgroupRoot)
+	if err != nil {
+		s.Result = healthchecks.CRITICAL
+		s.Details = err.Error()
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	if !limited {
+		s.Details = fmt.Sprintf("rss=%s limit=none", units.FormatBytes(rss))
+		return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+	}
+
+	usedRatio := ratio(rss, limit)
+	a := newAlerts()
+	a.ratio("Memory usage", usedRatio, m.WarningUsedRatio, m.CriticalUsedRatio)
+	a.reasons = append(a.reasons, fmt.Sprintf("rss=%s limit=%s (%.0f%%)", units.FormatBytes(rss), units.FormatBytes(limit), usedRatio*100))
+
+	s.Result = a.result
+	s.Details = strings.Join(a.reasons, "; ")
+	return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+}
+
+// Read the resident set size of the process from `status`
+func readRSS(procRoot string) (uint64, error) {
+	path := filepath.Join(procRoot, "self", "status")
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 3 && fields[0] == "VmRSS:" && fields[2] == "kB" {
+			kb, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("Error parsing VmRSS in %s: %s", path, err.Error())
+			}
+			return kb * 1024, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("VmRSS not found in %s", path)
+}
+
+// Read the memory limit of the cgroup of the process, returning false if it has no limit
+func readMemoryLimit(procRoot string, cgroupRoot string) (uint64, bool, error) {
+	// cgroup v2, the process cgroup is mounted at the root in most containers
+	candidates := []string{}
+	if cgroup, err := ioutil.ReadFile(filepath.Join(procRoot, "self", "cgroup")); err == nil {
+		for _, line := range strings.Split(string(cgroup), "\n") {
+			if strings.HasPrefix(line, "0::") {
+				candidates = append(candidates, filepath.Join(cgroupRoot, strings.TrimPrefix(line, "0::"), "memory.max"))
+			}
+		}
+	}
+	candidates = append(candidates, filepath.Join(cgroupRoot, "memory.max"))
+
+	for _, path := range candidates {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		value := strings.TrimSpace(string(data))
+		if value == "max" {
+			return 0, false, nil
+		}
+		limit, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("Error parsing memory limit in %s: %s", path, err.Error())
+		}
+		return limit, true, nil
+	}
+
+	// cgroup v1
+	path := filepath.Join(cgroupRoot, "memory", "memory.limit_in_bytes")
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+
+	limit, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("Error parsing memory limit in %s: %s", path, err.Error())
+	}
+	return limit, limit < cgroupV1Unlimited, nil
+}
@@ -0,0 +1,131 @@
+package systemsc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// Pressure stall information resources
+const (
+	ResourceCPU    = "cpu"
+	ResourceMemory = "memory"
+	ResourceIO     = "io"
+)
+
+// Pressure stall information averaging windows
+const (
+	Window10s  = "avg10"
+	Window60s  = "avg60"
+	Window300s = "avg300"
+)
+
+// PressureStatusChecker checks the pressure stall information (PSI) of resources, the percentage of time some or all
+// tasks were stalled waiting on the resource. Thresholds are percentages, from 0 to 100, and are disabled when 0.
+//
+// PSI requires Linux 4.20 or later with PSI enabled, a missing pressure file results in a CRITICAL status.
+type PressureStatusChecker struct {
+	Resources    []string // Optional resources to check, defaults to ResourceCPU and ResourceMemory
+	Window       string   // Optional averaging window, defaults to Window60s
+	WarningSome  float64  // Percentage of time some tasks were stalled
+	CriticalSome float64  // Percentage of time some tasks were stalled
+	WarningFull  float64  // Percentage of time all tasks were stalled, not reported for the CPU before Linux 5.13
+	CriticalFull float64  // Percentage of time all tasks were stalled, not reported for the CPU before Linux 5.13
+	ProcRoot     string   // Optional root of the proc filesystem, defaults to DefaultProcRoot
+}
+
+func (p PressureStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	s := healthchecks.Status{
+		Description: name,
+		Result:      healthchecks.OK,
+		Details:     "",
+	}
+
+	procRoot := p.ProcRoot
+	if procRoot == "" {
+		procRoot = DefaultProcRoot
+	}
+	resources := p.Resources
+	if len(resources) == 0 {
+		resources = []string{ResourceCPU, ResourceMemory}
+	}
+	window := p.Window
+	if window == "" {
+		window = Window60s
+	}
+
+	a := newAlerts()
+	summaries := []string{}
+	for _, resource := range resources {
+		pressure, err := readPressure(filepath.Join(procRoot, "pressure", resource))
+		if err != nil {
+			a.raise(healthchecks.CRITICAL, err.Error())
+			continue
+		}
+
+		some, hasSome := pressure["some"][window]
+		full, hasFull := pressure["full"][window]
+
+		summary := resource
+		if hasSome {
+			summary += fmt.Sprintf(" some=%.2f%%", some)
+			p.evaluate(a, fmt.Sprintf("%s some pressure of %.2f%%", resource, some), some, p.WarningSome, p.CriticalSome)
+		}
+		if hasFull {
+			summary += fmt.Sprintf(" full=%.2f%%", full)
+			p.evaluate(a, fmt.Sprintf("%s full pressure of %.2f%%", resource, full), full, p.WarningFull, p.CriticalFull)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	a.reasons = append(a.reasons, fmt.Sprintf("%s %s", window, strings.Join(summaries, ", ")))
+
+	s.Result = a.result
+	s.Details = strings.Join(a.reasons, "; ")
+	return healthchecks.StatusList{StatusList: []healthchecks.Status{s}}
+}
+
+func (p PressureStatusChecker) evaluate(a *alerts, pressure string, value float64, warning float64, critical float64) {
+	if critical > 0 && value >= critical {
+		a.raise(healthchecks.CRITICAL, fmt.Sprintf("%s exceeds critical threshold of %.2f%%", pressure, critical))
+	} else if warning > 0 && value >= warning {
+		a.raise(healthchecks.WARNING, fmt.Sprintf("%s exceeds warning threshold of %.2f%%", pressure, warning))
+	}
+}
+
+// Read a pressure file into its `some` and `full` lines, each a map of averaging window to percentage:
+// some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func readPressure(path string) (map[string]map[string]float64, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pressure := map[string]map[string]float64{}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		averages := map[string]float64{}
+		for _, field := range fields[1:] {
+			parts := strings.SplitN(field, "=", 2)
+			if len(parts) != 2 || parts[0] == "total" {
+				continue
+			}
+			value, err := strconv.ParseFloat(parts[1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("Error parsing %s in %s: %s", field, path, err.Error())
+			}
+			averages[parts[0]] = value
+		}
+		pressure[fields[0]] = averages
+	}
+
+	return pressure, nil
+}
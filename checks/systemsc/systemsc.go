@@ -0,0 +1,47 @@
+// Package systemsc checks the resources of the host and process: disk space, memory, file descriptors and pressure.
+// The checkers read the Linux proc and cgroup filesystems and are only available on Linux.
+package systemsc
+
+import (
+	"fmt"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// Default mount points of the proc and cgroup filesystems
+const (
+	DefaultProcRoot   = "/proc"
+	DefaultCgroupRoot = "/sys/fs/cgroup"
+)
+
+// Collects the reasons for the alert level of a status, keeping the highest level raised
+type alerts struct {
+	result  healthchecks.AlertLevel
+	reasons []string
+}
+
+func newAlerts() *alerts {
+	return &alerts{result: healthchecks.OK, reasons: []string{}}
+}
+
+func (a *alerts) raise(level healthchecks.AlertLevel, reason string) {
+	a.result = healthchecks.Worst(a.result, level)
+	a.reasons = append(a.reasons, reason)
+}
+
+// Raise an alert if the ratio exceeds a threshold, a threshold is disabled when 0
+func (a *alerts) ratio(label string, ratio float64, warning float64, critical float64) {
+	usage := fmt.Sprintf("%s of %.0f%%", label, ratio*100)
+	if critical > 0 && ratio >= critical {
+		a.raise(healthchecks.CRITICAL, fmt.Sprintf("%s exceeds critical threshold of %.0f%%", usage, critical*100))
+	} else if warning > 0 && ratio >= warning {
+		a.raise(healthchecks.WARNING, fmt.Sprintf("%s exceeds warning threshold of %.0f%%", usage, warning*100))
+	}
+}
+
+func ratio(used uint64, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(used) / float64(total)
+}
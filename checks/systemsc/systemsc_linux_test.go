@@ -0,0 +1,214 @@
+package systemsc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+func TestDisk(t *testing.T) {
+	statusChecker := DiskStatusChecker{MountPoints: []string{t.TempDir()}}
+	s := statusChecker.CheckStatus("The disk")
+
+	if len(s.StatusList) != 2 {
+		t.Fatalf("Length of StatusList should be 2, was %d", len(s.StatusList))
+	}
+	overall := s.StatusList[0]
+	if overall.Result != healthchecks.OK || overall.Details != "1 of 1 mount points are OK" {
+		t.Errorf("Status should be `OK` `1 of 1 mount points are OK`, was `%v`", overall)
+	}
+	actual := s.StatusList[1]
+	if actual.Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was `%s`: %s", actual.Result, actual.Details)
+	}
+	if !strings.HasPrefix(actual.Details, "used=") || !strings.Contains(actual.Details, " inodes=") {
+		t.Errorf("Details should report disk and inodes usage, was `%s`", actual.Details)
+	}
+}
+
+func TestDiskThreshold(t *testing.T) {
+	// Any filesystem in use exceeds a tiny ratio
+	statusChecker := DiskStatusChecker{MountPoints: []string{"/"}, CriticalUsedRatio: 0.0000001}
+	actual := statusChecker.CheckStatus("The disk").StatusList[1]
+
+	if actual.Description != "The disk (/)" {
+		t.Errorf("Description should be `The disk (/)`, was `%s`", actual.Description)
+	}
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+	if !strings.HasPrefix(actual.Details, "Disk usage of ") {
+		t.Errorf("Details should start with `Disk usage of `, was `%s`", actual.Details)
+	}
+}
+
+func TestDiskSecondMountPointFailing(t *testing.T) {
+	statusChecker := DiskStatusChecker{MountPoints: []string{t.TempDir(), "/does/not/exist"}}
+	s := statusChecker.CheckStatus("The disk")
+
+	if len(s.StatusList) != 3 {
+		t.Fatalf("Length of StatusList should be 3, was %d", len(s.StatusList))
+	}
+	overall := s.StatusList[0]
+	eDetails := "1 of 2 mount points are OK; failing: /does/not/exist"
+	if overall.Description != "The disk" || overall.Result != healthchecks.CRITICAL || overall.Details != eDetails {
+		t.Errorf("Status should be `The disk` `CRITICAL` `%s`, was `%v`", eDetails, overall)
+	}
+	if s.StatusList[1].Result != healthchecks.OK || s.StatusList[2].Result != healthchecks.CRITICAL {
+		t.Errorf("Mount point results should be `OK` then `CRITICAL`, was `%v`", s.StatusList[1:])
+	}
+}
+
+func TestDiskNoMountPoints(t *testing.T) {
+	s := DiskStatusChecker{}.CheckStatus("The disk")
+
+	if len(s.StatusList) != 1 || s.StatusList[0].Result != healthchecks.CRITICAL {
+		t.Errorf("StatusList should be a single `CRITICAL` status, was `%v`", s.StatusList)
+	}
+}
+
+func TestMemoryCgroupV2(t *testing.T) {
+	procRoot, cgroupRoot := t.TempDir(), t.TempDir()
+	writeFile(t, procRoot, "self/status", "Name:\ttest\nVmRSS:\t  819200 kB\n")
+	writeFile(t, procRoot, "self/cgroup", "0::/app\n")
+	writeFile(t, cgroupRoot, "app/memory.max", "1073741824\n")
+
+	statusChecker := MemoryStatusChecker{ProcRoot: procRoot, CgroupRoot: cgroupRoot, WarningUsedRatio: 0.7, CriticalUsedRatio: 0.9}
+	actual := statusChecker.CheckStatus("The memory").StatusList[0]
+
+	if actual.Result != healthchecks.WARNING {
+		t.Errorf("Result should be `WARNING`, was `%s`", actual.Result)
+	}
+	eDetails := "Memory usage of 78% exceeds warning threshold of 70%; rss=800.0MiB limit=1.0GiB (78%)"
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestMemoryCgroupV1(t *testing.T) {
+	procRoot, cgroupRoot := t.TempDir(), t.TempDir()
+	writeFile(t, procRoot, "self/status", "VmRSS:\t  1024 kB\n")
+	writeFile(t, cgroupRoot, "memory/memory.limit_in_bytes", "1048576\n")
+
+	statusChecker := MemoryStatusChecker{ProcRoot: procRoot, CgroupRoot: cgroupRoot, CriticalUsedRatio: 0.9}
+	actual := statusChecker.CheckStatus("The memory").StatusList[0]
+
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+	eDetails := "Memory usage of 100% exceeds critical threshold of 90%; rss=1.0MiB limit=1.0MiB (100%)"
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestMemoryUnlimited(t *testing.T) {
+	procRoot, cgroupRoot := t.TempDir(), t.TempDir()
+	writeFile(t, procRoot, "self/status", "VmRSS:\t  2048 kB\n")
+	writeFile(t, cgroupRoot, "memory.max", "max\n")
+
+	statusChecker := MemoryStatusChecker{ProcRoot: procRoot, CgroupRoot: cgroupRoot, CriticalUsedRatio: 0.9}
+	actual := statusChecker.CheckStatus("The memory").StatusList[0]
+
+	if actual.Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was `%s`", actual.Result)
+	}
+	eDetails := "rss=2.0MiB limit=none"
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestFileDescriptors(t *testing.T) {
+	procRoot := t.TempDir()
+	for _, fd := range []string{"0", "1", "2", "3"} {
+		writeFile(t, procRoot, "self/fd/"+fd, "")
+	}
+	writeFile(t, procRoot, "self/limits", ""+
+		"Limit                     Soft Limit           Hard Limit           Units     \n"+
+		"Max open files            5                    4096                 files     \n")
+
+	statusChecker := FileDescriptorStatusChecker{ProcRoot: procRoot, WarningUsedRatio: 0.75, CriticalUsedRatio: 0.9}
+	actual := statusChecker.CheckStatus("The file descriptors").StatusList[0]
+
+	if actual.Result != healthchecks.WARNING {
+		t.Errorf("Result should be `WARNING`, was `%s`", actual.Result)
+	}
+	eDetails := "File descriptors usage of 80% exceeds warning threshold of 75%; open=4 limit=5 (80%)"
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestFileDescriptorsSelf(t *testing.T) {
+	actual := FileDescriptorStatusChecker{}.CheckStatus("The file descriptors").StatusList[0]
+
+	if actual.Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was `%s`: %s", actual.Result, actual.Details)
+	}
+	if !strings.HasPrefix(actual.Details, "open=") {
+		t.Errorf("Details should start with `open=`, was `%s`", actual.Details)
+	}
+}
+
+func TestPressure(t *testing.T) {
+	procRoot := t.TempDir()
+	writeFile(t, procRoot, "pressure/cpu", ""+
+		"some avg10=12.50 avg60=25.00 avg300=5.00 total=123456\n"+
+		"full avg10=0.00 avg60=0.00 avg300=0.00 total=0\n")
+	writeFile(t, procRoot, "pressure/memory", ""+
+		"some avg10=1.00 avg60=2.00 avg300=3.00 total=1234\n"+
+		"full avg10=0.50 avg60=15.00 avg300=1.50 total=123\n")
+
+	statusChecker := PressureStatusChecker{ProcRoot: procRoot, WarningSome: 20, CriticalSome: 50, WarningFull: 5, CriticalFull: 10}
+	actual := statusChecker.CheckStatus("The pressure").StatusList[0]
+
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+	eDetails := "cpu some pressure of 25.00% exceeds warning threshold of 20.00%; " +
+		"memory full pressure of 15.00% exceeds critical threshold of 10.00%; " +
+		"avg60 cpu some=25.00% full=0.00%, memory some=2.00% full=15.00%"
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestPressureWindow(t *testing.T) {
+	procRoot := t.TempDir()
+	writeFile(t, procRoot, "pressure/io", "some avg10=30.00 avg60=2.00 avg300=1.00 total=1\n")
+
+	statusChecker := PressureStatusChecker{ProcRoot: procRoot, Resources: []string{ResourceIO}, Window: Window10s, WarningSome: 20}
+	actual := statusChecker.CheckStatus("The pressure").StatusList[0]
+
+	if actual.Result != healthchecks.WARNING {
+		t.Errorf("Result should be `WARNING`, was `%s`", actual.Result)
+	}
+	eDetails := "io some pressure of 30.00% exceeds warning threshold of 20.00%; avg10 io some=30.00%"
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestPressureMissing(t *testing.T) {
+	statusChecker := PressureStatusChecker{ProcRoot: t.TempDir()}
+	actual := statusChecker.CheckStatus("The pressure").StatusList[0]
+
+	if actual.Result != healthchecks.CRITICAL {
+		t.Errorf("Result should be `CRITICAL`, was `%s`", actual.Result)
+	}
+}
+
+func writeFile(t *testing.T, root string, name string, content string) {
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,226 @@
+package tlssc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// Default thresholds and timeout of a CertificateStatusChecker
+const (
+	DefaultWarningDays  = 30
+	DefaultCriticalDays = 7
+	DefaultTimeout      = 5 * time.Second
+)
+
+// CertificateStatusChecker checks the expiry of a certificate chain, either presented by a remote TLS endpoint at
+// Address or read from the PEM file at PEMFile, whose first certificate is the leaf.
+//
+// The details report the subject, issuer and days remaining of the leaf certificate. The status is WARN or CRITICAL
+// when the certificate expires within WarningDays or CriticalDays, and CRITICAL if it expired or isn't valid yet.
+// When Roots is set, the chain is also validated against it, along with the host name of a remote endpoint, and a
+// validation failure results in a CRITICAL status. The expiry is then that of the first certificate to expire in
+// the verified chain, so an expired certificate presented by the server but not needed to validate it, such as an
+// outdated cross-signed root, is ignored. Without Roots only the leaf certificate is checked.
+type CertificateStatusChecker struct {
+	Address      string         // The host:port of the TLS endpoint to check, if PEMFile is empty
+	ServerName   string         // Optional server name sent and validated, defaults to the host of Address
+	PEMFile      string         // The PEM file to check, if Address is empty
+	Roots        *x509.CertPool // Optional root certificates to validate the chain against, the chain isn't validated if nil
+	WarningDays  int            // Optional days before expiry from which a warning is raised, defaults to DefaultWarningDays
+	CriticalDays int            // Optional days before expiry from which a critical alert is raised, defaults to DefaultCriticalDays
+	Timeout      time.Duration  // Optional timeout to connect to Address, defaults to DefaultTimeout
+}
+
+func (c CertificateStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	chain, err := c.chain()
+	if err != nil {
+		return certificateStatus(name, healthchecks.CRITICAL, err.Error())
+	}
+
+	leaf := chain[0]
+	now := time.Now()
+	details := fmt.Sprintf(
+		"subject=%s issuer=%s expires=%s days_remaining=%d",
+		leaf.Subject,
+		leaf.Issuer,
+		leaf.NotAfter.UTC().Format(time.RFC3339),
+		daysRemaining(leaf, now),
+	)
+
+	if now.Before(leaf.NotBefore) {
+		return certificateStatus(name, healthchecks.CRITICAL, fmt.Sprintf("Certificate is not valid before %s; %s", leaf.NotBefore.UTC().Format(time.RFC3339), details))
+	}
+
+	if now.After(leaf.NotAfter) {
+		return certificateStatus(name, healthchecks.CRITICAL, fmt.Sprintf("Certificate expired on %s; %s", leaf.NotAfter.UTC().Format(time.RFC3339), details))
+	}
+
+	expiring := leaf
+	if c.Roots != nil {
+		verified, err := c.verify(chain, now)
+		if err != nil {
+			return certificateStatus(name, healthchecks.CRITICAL, fmt.Sprintf("Invalid certificate chain: %s; %s", err.Error(), details))
+		}
+		expiring = firstToExpire(verified)
+	}
+
+	subject := "Certificate"
+	if expiring != leaf {
+		subject = fmt.Sprintf("Chain certificate %s", expiring.Subject)
+	}
+
+	days := daysRemaining(expiring, now)
+	criticalDays := c.CriticalDays
+	if criticalDays <= 0 {
+		criticalDays = DefaultCriticalDays
+	}
+	if days < criticalDays {
+		return certificateStatus(name, healthchecks.CRITICAL, fmt.Sprintf("%s expires in %d days, below critical threshold of %d days; %s", subject, days, criticalDays, details))
+	}
+
+	warningDays := c.WarningDays
+	if warningDays <= 0 {
+		warningDays = DefaultWarningDays
+	}
+	if days < warningDays {
+		return certificateStatus(name, healthchecks.WARNING, fmt.Sprintf("%s expires in %d days, below warning threshold of %d days; %s", subject, days, warningDays, details))
+	}
+
+	return certificateStatus(name, healthchecks.OK, details)
+}
+
+// Get the certificate chain to check, starting with the leaf certificate
+func (c CertificateStatusChecker) chain() ([]*x509.Certificate, error) {
+	if c.Address == "" {
+		return readPEMFile(c.PEMFile)
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	// The chain is validated after the handshake, to report on invalid certificates rather than failing
+	config := &tls.Config{ServerName: c.serverName(), InsecureSkipVerify: true}
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", c.Address, config)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	chain := conn.ConnectionState().PeerCertificates
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("No certificate presented by %s", c.Address)
+	}
+
+	return chain, nil
+}
+
+func (c CertificateStatusChecker) serverName() string {
+	if c.ServerName != "" {
+		return c.ServerName
+	}
+
+	host, _, err := net.SplitHostPort(c.Address)
+	if err != nil {
+		return c.Address
+	}
+	return host
+}
+
+// Verify the chain against the roots, returning the verified chains
+func (c CertificateStatusChecker) verify(chain []*x509.Certificate, now time.Time) ([][]*x509.Certificate, error) {
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	options := x509.VerifyOptions{
+		Roots:         c.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	}
+	if c.Address != "" {
+		options.DNSName = c.serverName()
+	}
+
+	return chain[0].Verify(options)
+}
+
+// Get the first certificate to expire of the verified chain which stays valid the longest, a chain is only as valid
+// as its first certificate to expire
+func firstToExpire(verified [][]*x509.Certificate) *x509.Certificate {
+	var best *x509.Certificate
+	for _, chain := range verified {
+		expiring := chain[0]
+		for _, cert := range chain[1:] {
+			if cert.NotAfter.Before(expiring.NotAfter) {
+				expiring = cert
+			}
+		}
+		if best == nil || expiring.NotAfter.After(best.NotAfter) {
+			best = expiring
+		}
+	}
+	return best
+}
+
+func readPEMFile(path string) ([]*x509.Certificate, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := []*x509.Certificate{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing certificate in %s: %s", path, err.Error())
+		}
+		chain = append(chain, cert)
+	}
+
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("No certificate found in %s", path)
+	}
+
+	return chain, nil
+}
+
+// Number of whole days until the certificate expires, negative once expired
+func daysRemaining(cert *x509.Certificate, now time.Time) int {
+	remaining := cert.NotAfter.Sub(now)
+	days := int(remaining / (24 * time.Hour))
+	if remaining < 0 && remaining%(24*time.Hour) != 0 {
+		days--
+	}
+	return days
+}
+
+func certificateStatus(name string, result healthchecks.AlertLevel, details string) healthchecks.StatusList {
+	return healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: name,
+				Result:      result,
+				Details:     details,
+			},
+		},
+	}
+}
@@ -0,0 +1,243 @@
+package tlssc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+func TestCertificateOK(t *testing.T) {
+	ca := newCertificate(t, "Test CA", nil, 365*24*time.Hour)
+	leaf := newCertificate(t, "leaf.example.com", ca, 90*24*time.Hour+time.Hour)
+
+	certificateStatusChecker := CertificateStatusChecker{PEMFile: writePEMFile(t, leaf, ca), Roots: pool(ca)}
+	s := certificateStatusChecker.CheckStatus("The certificate")
+
+	if len(s.StatusList) != 1 {
+		t.Fatalf("Length of StatusList should be 1, was %d", len(s.StatusList))
+	}
+
+	actual := s.StatusList[0]
+	if actual.Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was `%s`", actual.Result)
+	}
+
+	eDetails := fmt.Sprintf("subject=CN=leaf.example.com issuer=CN=Test CA expires=%s days_remaining=90", leaf.cert.NotAfter.UTC().Format(time.RFC3339))
+	if actual.Details != eDetails {
+		t.Errorf("Details should be `%s`, was `%s`", eDetails, actual.Details)
+	}
+}
+
+func TestCertificateThresholds(t *testing.T) {
+	ca := newCertificate(t, "Test CA", nil, 365*24*time.Hour)
+
+	tests := []struct {
+		validity time.Duration
+		result   healthchecks.AlertLevel
+		details  string
+	}{
+		{20*24*time.Hour + time.Hour, healthchecks.WARNING, "Certificate expires in 20 days, below warning threshold of 30 days; "},
+		{3*24*time.Hour + time.Hour, healthchecks.CRITICAL, "Certificate expires in 3 days, below critical threshold of 7 days; "},
+		{-time.Hour, healthchecks.CRITICAL, "Certificate expired on "},
+	}
+
+	for _, test := range tests {
+		leaf := newCertificate(t, "leaf.example.com", ca, test.validity)
+		actual := CertificateStatusChecker{PEMFile: writePEMFile(t, leaf)}.CheckStatus("The certificate").StatusList[0]
+
+		if actual.Result != test.result || !strings.HasPrefix(actual.Details, test.details) {
+			t.Errorf("Status should be `%s` starting with `%s`, was `%s` with `%s`", test.result, test.details, actual.Result, actual.Details)
+		}
+	}
+
+	// Custom thresholds
+	leaf := newCertificate(t, "leaf.example.com", ca, 20*24*time.Hour+time.Hour)
+	actual := CertificateStatusChecker{PEMFile: writePEMFile(t, leaf), WarningDays: 10, CriticalDays: 5}.CheckStatus("The certificate").StatusList[0]
+	if actual.Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was `%s`", actual.Result)
+	}
+}
+
+func TestCertificateChainExpiry(t *testing.T) {
+	ca := newCertificate(t, "Test CA", nil, 10*24*time.Hour+time.Hour)
+	leaf := newCertificate(t, "leaf.example.com", ca, 90*24*time.Hour)
+
+	actual := CertificateStatusChecker{PEMFile: writePEMFile(t, leaf, ca), Roots: pool(ca)}.CheckStatus("The certificate").StatusList[0]
+
+	eDetails := "Chain certificate CN=Test CA expires in 10 days, below warning threshold of 30 days; subject=CN=leaf.example.com"
+	if actual.Result != healthchecks.WARNING || !strings.HasPrefix(actual.Details, eDetails) {
+		t.Errorf("Status should be `WARNING` starting with `%s`, was `%s` with `%s`", eDetails, actual.Result, actual.Details)
+	}
+
+	// Without roots only the leaf certificate is checked
+	actual = CertificateStatusChecker{PEMFile: writePEMFile(t, leaf, ca)}.CheckStatus("The certificate").StatusList[0]
+	if actual.Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was `%s` with `%s`", actual.Result, actual.Details)
+	}
+}
+
+func TestCertificateUnusedExpiredChainCertificate(t *testing.T) {
+	ca := newCertificate(t, "Test CA", nil, 365*24*time.Hour)
+	expiredCA := newCertificate(t, "Expired CA", nil, -time.Hour)
+	leaf := newCertificate(t, "leaf.example.com", ca, 90*24*time.Hour+time.Hour)
+
+	// The expired certificate is presented but not part of the verified chain
+	actual := CertificateStatusChecker{PEMFile: writePEMFile(t, leaf, expiredCA, ca), Roots: pool(ca)}.CheckStatus("The certificate").StatusList[0]
+	if actual.Result != healthchecks.OK {
+		t.Errorf("Result should be `OK`, was `%s` with `%s`", actual.Result, actual.Details)
+	}
+}
+
+func TestCertificateUntrusted(t *testing.T) {
+	ca := newCertificate(t, "Test CA", nil, 365*24*time.Hour)
+	otherCA := newCertificate(t, "Other CA", nil, 365*24*time.Hour)
+	leaf := newCertificate(t, "leaf.example.com", ca, 90*24*time.Hour)
+
+	actual := CertificateStatusChecker{PEMFile: writePEMFile(t, leaf), Roots: pool(otherCA)}.CheckStatus("The certificate").StatusList[0]
+
+	eDetails := "Invalid certificate chain: x509: certificate signed by unknown authority"
+	if actual.Result != healthchecks.CRITICAL || !strings.HasPrefix(actual.Details, eDetails) {
+		t.Errorf("Status should be `CRITICAL` starting with `%s`, was `%s` with `%s`", eDetails, actual.Result, actual.Details)
+	}
+}
+
+func TestCertificateMissingFile(t *testing.T) {
+	actual := CertificateStatusChecker{PEMFile: filepath.Join(t.TempDir(), "missing.pem")}.CheckStatus("The certificate").StatusList[0]
+
+	if actual.Result != healthchecks.CRITICAL || !strings.Contains(actual.Details, "no such file or directory") {
+		t.Errorf("Status should be `CRITICAL` with a missing file, was `%s` with `%s`", actual.Result, actual.Details)
+	}
+}
+
+func TestCertificateRemote(t *testing.T) {
+	ca := newCertificate(t, "Test CA", nil, 365*24*time.Hour)
+	leaf := newCertificate(t, "127.0.0.1", ca, 90*24*time.Hour+time.Hour)
+	address := listenTLS(t, leaf, ca)
+
+	actual := CertificateStatusChecker{Address: address, Roots: pool(ca)}.CheckStatus("The certificate").StatusList[0]
+	if actual.Result != healthchecks.OK || !strings.HasPrefix(actual.Details, "subject=CN=127.0.0.1 issuer=CN=Test CA ") {
+		t.Errorf("Status should be `OK` with the certificate, was `%s` with `%s`", actual.Result, actual.Details)
+	}
+
+	// The certificate is not valid for another name
+	actual = CertificateStatusChecker{Address: address, ServerName: "other.example.com", Roots: pool(ca)}.CheckStatus("The certificate").StatusList[0]
+	if actual.Result != healthchecks.CRITICAL || !strings.HasPrefix(actual.Details, "Invalid certificate chain: x509: certificate is not valid for any names, but wanted to match other.example.com") {
+		t.Errorf("Status should be `CRITICAL` with an invalid name, was `%s` with `%s`", actual.Result, actual.Details)
+	}
+}
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// Create a certificate valid until now + validity, self-signed if parent is nil
+func newCertificate(t *testing.T, commonName string, parent *testCertificate, validity time.Duration) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-24 * time.Hour),
+		NotAfter:     time.Now().Add(validity),
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else if ip := net.ParseIP(commonName); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{commonName}
+	}
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCertificate{cert: cert, key: key}
+}
+
+func writePEMFile(t *testing.T, chain ...*testCertificate) string {
+	t.Helper()
+
+	data := []byte{}
+	for _, c := range chain {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw})...)
+	}
+
+	path := filepath.Join(t.TempDir(), "chain.pem")
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func pool(certificates ...*testCertificate) *x509.CertPool {
+	p := x509.NewCertPool()
+	for _, c := range certificates {
+		p.AddCert(c.cert)
+	}
+	return p
+}
+
+// Listen on a local port presenting the chain, returning its address
+func listenTLS(t *testing.T, leaf *testCertificate, chain ...*testCertificate) string {
+	t.Helper()
+
+	certificate := tls.Certificate{Certificate: [][]byte{leaf.cert.Raw}, PrivateKey: leaf.key}
+	for _, c := range chain {
+		certificate.Certificate = append(certificate.Certificate, c.cert.Raw)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{certificate}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				conn.(*tls.Conn).Handshake()
+				conn.Close()
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
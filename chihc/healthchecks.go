@@ -0,0 +1,32 @@
+package chihc
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hootsuite/healthchecks"
+)
+
+// HealthChecksEndpoints registers the status check routes on a chi router under the given mount prefix, e.g. `/status`.
+//
+// `{prefix}/{slug}` serves the V1 API and `{prefix}/v2/{slug}` serves the V2 API for GET and HEAD requests.
+// POST requests to `{prefix}/v2/{slug}` push the status of a healthchecks.PassiveCheck endpoint.
+func HealthChecksEndpoints(r chi.Router, prefix string, statusEndpoints []healthchecks.StatusEndpoint, aboutFilePath string, versionFilePath string, customData map[string]interface{}) {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	v1 := endpointHandler(healthchecks.APIV1, statusEndpoints, aboutFilePath, versionFilePath, customData)
+	v2 := endpointHandler(healthchecks.APIV2, statusEndpoints, aboutFilePath, versionFilePath, customData)
+
+	r.Get(prefix+"/{slug}", v1)
+	r.Head(prefix+"/{slug}", v1)
+	r.Get(prefix+"/v2/{slug}", v2)
+	r.Head(prefix+"/v2/{slug}", v2)
+	r.Post(prefix+"/v2/{slug}", v2)
+}
+
+func endpointHandler(apiVersion healthchecks.APIVersion, statusEndpoints []healthchecks.StatusEndpoint, aboutFilePath string, versionFilePath string, customData map[string]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		healthchecks.ServeEndpoint(w, r, apiVersion, chi.URLParam(r, "slug"), statusEndpoints, aboutFilePath, versionFilePath, customData)
+	}
+}
@@ -0,0 +1,25 @@
+package chihc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hootsuite/healthchecks"
+	"github.com/hootsuite/healthchecks/internal/adaptertest"
+)
+
+func TestHealthChecksEndpoints(t *testing.T) {
+	adaptertest.TestConformance(t, serve)
+}
+
+/* HELPER FUNCTIONS */
+func serve(prefix string, statusEndpoints []healthchecks.StatusEndpoint, req *http.Request) (int, string) {
+	r := chi.NewRouter()
+	HealthChecksEndpoints(r, prefix, statusEndpoints, "../test/about.json", "../test/version.txt", nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code, w.Body.String()
+}
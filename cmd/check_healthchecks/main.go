@@ -0,0 +1,148 @@
+// Command check_healthchecks is a Nagios / Icinga plugin checking a service through its healthchecks endpoints.
+//
+// It queries the aggregate or a single status endpoint of the V1 or V2 API and exits with the matching plugin code:
+// 0 for OK, 1 for WARN, 2 for CRIT and 3 if the service could not be checked.
+//
+//	check_healthchecks -url http://localhost:8080/status -endpoint aggregate -type internal
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// Maximum size of a response body read
+const maxResponseBytes = 1024 * 1024
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout))
+}
+
+// Run the plugin with command line arguments, writing its output to stdout and returning its exit code
+func run(args []string, stdout io.Writer) int {
+	flags := flag.NewFlagSet("check_healthchecks", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	baseUrl := flags.String("url", "http://localhost:8080/status", "Base url of the status endpoints")
+	endpoint := flags.String("endpoint", "aggregate", "The endpoint to check, `aggregate` or the slug of a status endpoint")
+	apiVersion := flags.Int("api", 2, "Version of the healthchecks API, 1 or 2")
+	typeFilter := flags.String("type", "", "Optional type of the aggregated checks, `internal` or `external`")
+	timeout := flags.Duration("timeout", 10*time.Second, "Timeout of the request")
+
+	if err := flags.Parse(args); err != nil {
+		return unknown(stdout, err)
+	}
+	if *apiVersion != 1 && *apiVersion != 2 {
+		return unknown(stdout, fmt.Errorf("Unsupported API version %d, expecting 1 or 2", *apiVersion))
+	}
+
+	start := time.Now()
+	s, err := checkStatus(*baseUrl, *endpoint, *apiVersion, *typeFilter, *timeout)
+	if err != nil {
+		return unknown(stdout, err)
+	}
+	elapsed := time.Since(start).Seconds()
+
+	fmt.Fprint(stdout, healthchecks.FormatNagios(
+		healthchecks.StatusList{StatusList: []healthchecks.Status{s}},
+		[]healthchecks.NagiosPerfdata{{Label: "time", Value: elapsed, UOM: "s"}},
+	))
+	return healthchecks.NagiosExitCode(s.Result)
+}
+
+// Report an error checking the service as UNKNOWN
+func unknown(stdout io.Writer, err error) int {
+	s := healthchecks.Status{Description: "check_healthchecks", Result: "UNKNOWN", Details: err.Error()}
+	fmt.Fprint(stdout, healthchecks.FormatNagios(healthchecks.StatusList{StatusList: []healthchecks.Status{s}}, nil))
+	return healthchecks.NagiosExitCode(s.Result)
+}
+
+// Query a status endpoint and decode its V1 or V2 response
+func checkStatus(baseUrl string, endpoint string, apiVersion int, typeFilter string, timeout time.Duration) (healthchecks.Status, error) {
+	path := url.PathEscape(endpoint)
+	if apiVersion == 2 {
+		path = "v2/" + path
+	}
+	u := fmt.Sprintf("%s/%s", strings.TrimSuffix(baseUrl, "/"), path)
+	if typeFilter != "" {
+		u += "?type=" + url.QueryEscape(typeFilter)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(u)
+	if err != nil {
+		return healthchecks.Status{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return healthchecks.Status{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return healthchecks.Status{}, fmt.Errorf("Invalid response. Code: %d, Body: %s", resp.StatusCode, body)
+	}
+
+	var s healthchecks.Status
+	if apiVersion == 2 {
+		s, err = decodeV2(body)
+	} else {
+		s, err = decodeV1(body)
+	}
+	if err != nil {
+		return healthchecks.Status{}, fmt.Errorf("Error decoding json response: %s", err.Error())
+	}
+	if s.Description == "" {
+		s.Description = endpoint
+	}
+
+	switch s.Result {
+	case healthchecks.OK, healthchecks.WARNING, healthchecks.CRITICAL:
+		return s, nil
+	default:
+		return healthchecks.Status{}, fmt.Errorf("Unknown result `%s`", s.Result)
+	}
+}
+
+// Decode a V2 response, a single status
+func decodeV2(body []byte) (healthchecks.Status, error) {
+	s := healthchecks.Status{}
+	err := json.Unmarshal(body, &s)
+	return s, err
+}
+
+// Decode a V1 response, `["OK"]` or the result followed by the status, e.g. `["CRIT",{"description":...}]`
+func decodeV1(body []byte) (healthchecks.Status, error) {
+	response := []json.RawMessage{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return healthchecks.Status{}, err
+	}
+	if len(response) == 0 {
+		return healthchecks.Status{}, fmt.Errorf("Empty response")
+	}
+
+	s := healthchecks.Status{}
+	if err := json.Unmarshal(response[0], &s.Result); err != nil {
+		return healthchecks.Status{}, err
+	}
+	if len(response) > 1 {
+		if err := json.Unmarshal(response[1], &s); err != nil {
+			return healthchecks.Status{}, err
+		}
+	}
+	if s.Details == "" && s.Result == healthchecks.OK {
+		s.Details = "The service is OK"
+	}
+
+	return s, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+var testStatusEndpoints = []healthchecks.StatusEndpoint{
+	{
+		Name:          "AAA",
+		Slug:          "aaa",
+		Type:          "internal",
+		IsTraversable: false,
+		StatusCheck:   MockStatusChecker{healthchecks.OK, "all good"},
+		TraverseCheck: nil,
+	},
+	{
+		Name:          "BBB",
+		Slug:          "bbb",
+		Type:          "external",
+		IsTraversable: false,
+		StatusCheck:   MockStatusChecker{healthchecks.WARNING, "slow"},
+		TraverseCheck: nil,
+	},
+}
+
+func TestCheckHealthchecks(t *testing.T) {
+	server := httptest.NewServer(healthchecks.Handler(testStatusEndpoints, "../../test/about.json", "../../test/version.txt", nil))
+	defer server.Close()
+
+	expected := []struct {
+		args     []string
+		exitCode int
+		output   string
+	}{
+		{[]string{"-endpoint", "aaa"}, 0, `^SERVICE OK - all good \| 'time'=[0-9.e-]+s\n$`},
+		{[]string{"-endpoint", "aaa", "-api", "1"}, 0, `^SERVICE OK - The service is OK \| 'time'=[0-9.e-]+s\n$`},
+		{[]string{"-endpoint", "bbb", "-api", "1"}, 1, `^SERVICE WARNING - slow \| 'time'=[0-9.e-]+s\n$`},
+		{[]string{}, 1, `^SERVICE WARNING - slow \| 'time'=[0-9.e-]+s\n$`},
+		{[]string{"-type", "internal"}, 0, `^SERVICE OK - All checks are OK \| 'time'=[0-9.e-]+s\n$`},
+		{[]string{"-type", "unknown"}, 2, `^SERVICE CRITICAL - Unknown check type given for aggregate check \| 'time'=[0-9.e-]+s\n$`},
+		{[]string{"-endpoint", "zzz"}, 3, `^SERVICE UNKNOWN - Invalid response. Code: 404, Body: `},
+		{[]string{"-api", "3"}, 3, `^SERVICE UNKNOWN - Unsupported API version 3, expecting 1 or 2\n$`},
+	}
+	for _, e := range expected {
+		output := &strings.Builder{}
+		exitCode := run(append([]string{"-url", server.URL + "/status"}, e.args...), output)
+
+		if exitCode != e.exitCode {
+			t.Errorf("Exit code of %v should be %d, was %d", e.args, e.exitCode, exitCode)
+		}
+		if !regexp.MustCompile(e.output).MatchString(output.String()) {
+			t.Errorf("Output of %v should match `%s`, was `%s`", e.args, e.output, output.String())
+		}
+	}
+}
+
+func TestCheckHealthchecksUnreachable(t *testing.T) {
+	output := &strings.Builder{}
+	exitCode := run([]string{"-url", "http://127.0.0.1:1/status"}, output)
+
+	if exitCode != 3 {
+		t.Errorf("Exit code should be 3, was %d", exitCode)
+	}
+	if !strings.HasPrefix(output.String(), "SERVICE UNKNOWN - ") {
+		t.Errorf("Output should start with `SERVICE UNKNOWN - `, was `%s`", output.String())
+	}
+}
+
+// Mocks
+
+type MockStatusChecker struct {
+	Result  healthchecks.AlertLevel
+	Details string
+}
+
+func (m MockStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	return healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: name,
+				Result:      m.Result,
+				Details:     m.Details,
+			},
+		},
+	}
+}
@@ -0,0 +1,163 @@
+package healthchecks
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// NamedCheck is a child check of a composite StatusCheck
+type NamedCheck struct {
+	Name        string
+	StatusCheck StatusCheck
+}
+
+// Downgrade maps the result of a child check to the result it contributes to a WorstOf check, e.g.
+// `Downgrade{CRITICAL: WARNING}` for children that are not critical to the service. Results missing from the map are
+// kept as is.
+type Downgrade map[AlertLevel]AlertLevel
+
+// A StatusCheck combining the results of its children, run concurrently. The returned StatusList starts with the
+// combined status followed by the StatusList of every child, in order.
+type compositeCheck struct {
+	children []NamedCheck
+	combine  func(results []Status) (AlertLevel, string)
+}
+
+// AllOf creates a StatusCheck that is OK when every child is OK. It is CRITICAL if any child is CRITICAL, else WARN if
+// any child is WARN.
+func AllOf(children ...NamedCheck) StatusCheck {
+	return Quorum(len(children), children...)
+}
+
+// AnyOf creates a StatusCheck that is up when any child is up, i.e. OK or WARN. It is CRITICAL if every child is
+// CRITICAL, WARN if any child is not OK.
+func AnyOf(children ...NamedCheck) StatusCheck {
+	return Quorum(1, children...)
+}
+
+// Quorum creates a StatusCheck that is up when at least n children are up, i.e. OK or WARN. It is CRITICAL below the
+// quorum, WARN if the quorum is met but any child is not OK.
+func Quorum(n int, children ...NamedCheck) StatusCheck {
+	return compositeCheck{
+		children: children,
+		combine: func(results []Status) (AlertLevel, string) {
+			up := 0
+			for _, result := range results {
+				if result.Result == OK || result.Result == WARNING {
+					up++
+				}
+			}
+
+			result := worstResult(results)
+			if up < n {
+				result = CRITICAL
+			} else if result == CRITICAL {
+				result = WARNING
+			}
+
+			details := fmt.Sprintf("%d of %d checks are up, quorum of %d", up, len(results), n)
+			return result, joinDetails(details, failingChecks(results))
+		},
+	}
+}
+
+// WorstOf creates a StatusCheck whose result is the worst result of its children, after mapping each child's result
+// through downgrade
+func WorstOf(downgrade Downgrade, children ...NamedCheck) StatusCheck {
+	return compositeCheck{
+		children: children,
+		combine: func(results []Status) (AlertLevel, string) {
+			downgraded := make([]Status, len(results))
+			for i, result := range results {
+				downgraded[i] = result
+				if level, ok := downgrade[result.Result]; ok {
+					downgraded[i].Result = level
+				}
+			}
+
+			result := worstResult(downgraded)
+			details := fmt.Sprintf("Worst of %d checks is %s", len(results), result)
+			if raw := worstResult(results); raw != result {
+				details = fmt.Sprintf("%s, downgraded from %s", details, raw)
+			}
+			return result, joinDetails(details, failingChecks(results))
+		},
+	}
+}
+
+func (c compositeCheck) CheckStatus(name string) StatusList {
+	statusLists := make([]StatusList, len(c.children))
+
+	var wg sync.WaitGroup
+	wg.Add(len(c.children))
+	for i, child := range c.children {
+		go func(i int, child NamedCheck) {
+			defer wg.Done()
+			statusLists[i] = child.StatusCheck.CheckStatus(child.Name)
+		}(i, child)
+	}
+	wg.Wait()
+
+	results := make([]Status, len(statusLists))
+	children := []Status{}
+	for i, statusList := range statusLists {
+		if len(statusList.StatusList) == 0 {
+			statusList.StatusList = []Status{
+				{
+					Description: c.children[i].Name,
+					Result:      CRITICAL,
+					Details:     "StatusList empty",
+				},
+			}
+		}
+		results[i] = statusList.StatusList[0]
+		children = append(children, statusList.StatusList...)
+	}
+
+	result, details := c.combine(results)
+	s := Status{
+		Description: name,
+		Result:      result,
+		Details:     details,
+	}
+
+	return StatusList{StatusList: append([]Status{s}, children...)}
+}
+
+// Get the worst result: CRIT, WARN then OK. A result other than OK or WARN, which would fail Aggregate, counts as CRIT.
+func worstResult(results []Status) AlertLevel {
+	levels := make([]AlertLevel, len(results))
+	for i, result := range results {
+		levels[i] = result.Result
+	}
+	return Worst(levels...)
+}
+
+// List the children that are not OK by result, e.g. `CRIT: cache-1, cache-2; WARN: cache-3`
+func failingChecks(results []Status) string {
+	names := map[AlertLevel][]string{}
+	for _, result := range results {
+		if level := Worst(result.Result); level != OK {
+			names[level] = append(names[level], result.Description)
+		}
+	}
+
+	failing := []string{}
+	for _, level := range []AlertLevel{CRITICAL, WARNING} {
+		if len(names[level]) > 0 {
+			failing = append(failing, fmt.Sprintf("%s: %s", level, strings.Join(names[level], ", ")))
+		}
+	}
+	return strings.Join(failing, "; ")
+}
+
+func joinDetails(details ...string) string {
+	nonEmpty := []string{}
+	for _, d := range details {
+		if d != "" {
+			nonEmpty = append(nonEmpty, d)
+		}
+	}
+	return strings.Join(nonEmpty, "; ")
+}
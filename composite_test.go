@@ -0,0 +1,162 @@
+package healthchecks
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQuorum(t *testing.T) {
+	nodes := func(results ...AlertLevel) []NamedCheck {
+		checks := []NamedCheck{}
+		for i, result := range results {
+			name := string(rune('a'+i)) + "-node"
+			checks = append(checks, NamedCheck{Name: name, StatusCheck: MockStatusChecker{name, result, string(result)}})
+		}
+		return checks
+	}
+
+	expected := []struct {
+		check   StatusCheck
+		result  AlertLevel
+		details string
+	}{
+		{Quorum(3, nodes(OK, OK, OK, OK, OK)...), OK, "5 of 5 checks are up, quorum of 3"},
+		{Quorum(3, nodes(OK, CRITICAL, OK, CRITICAL, OK)...), WARNING, "3 of 5 checks are up, quorum of 3; CRIT: b-node, d-node"},
+		{Quorum(3, nodes(OK, CRITICAL, WARNING, CRITICAL, CRITICAL)...), CRITICAL, "2 of 5 checks are up, quorum of 3; CRIT: b-node, d-node, e-node; WARN: c-node"},
+		{AllOf(nodes(OK, OK)...), OK, "2 of 2 checks are up, quorum of 2"},
+		{AllOf(nodes(OK, WARNING)...), WARNING, "2 of 2 checks are up, quorum of 2; WARN: b-node"},
+		{AllOf(nodes(OK, CRITICAL)...), CRITICAL, "1 of 2 checks are up, quorum of 2; CRIT: b-node"},
+		{AnyOf(nodes(CRITICAL, OK)...), WARNING, "1 of 2 checks are up, quorum of 1; CRIT: a-node"},
+		{AnyOf(nodes(CRITICAL, CRITICAL)...), CRITICAL, "0 of 2 checks are up, quorum of 1; CRIT: a-node, b-node"},
+	}
+	for _, e := range expected {
+		s := e.check.CheckStatus("The cache")
+		actual := s.StatusList[0]
+
+		if actual.Description != "The cache" || actual.Result != e.result || actual.Details != e.details {
+			t.Errorf("Status should be `The cache` `%s` `%s`, was `%v`", e.result, e.details, actual)
+		}
+	}
+}
+
+func TestCompositeChildren(t *testing.T) {
+	nested := AllOf(
+		NamedCheck{Name: "Replica 1", StatusCheck: MockStatusChecker{"Replica 1", OK, "all good"}},
+		NamedCheck{Name: "Replica 2", StatusCheck: MockStatusChecker{"Replica 2", WARNING, "lagging"}},
+	)
+	s := AnyOf(
+		NamedCheck{Name: "Primary", StatusCheck: MockStatusChecker{"Primary", OK, "all good"}},
+		NamedCheck{Name: "Replicas", StatusCheck: nested},
+	).CheckStatus("The database")
+
+	expected := []Status{
+		{Description: "The database", Result: WARNING, Details: "2 of 2 checks are up, quorum of 1; WARN: Replicas"},
+		{Description: "Primary", Result: OK, Details: "all good"},
+		{Description: "Replicas", Result: WARNING, Details: "2 of 2 checks are up, quorum of 2; WARN: Replica 2"},
+		{Description: "Replica 1", Result: OK, Details: "all good"},
+		{Description: "Replica 2", Result: WARNING, Details: "lagging"},
+	}
+	if len(s.StatusList) != len(expected) {
+		t.Fatalf("Length of StatusList should be %d, was %d", len(expected), len(s.StatusList))
+	}
+	for i, e := range expected {
+		if s.StatusList[i] != e {
+			t.Errorf("Status should be `%v`, was `%v`", e, s.StatusList[i])
+		}
+	}
+}
+
+func TestWorstOf(t *testing.T) {
+	children := []NamedCheck{
+		{Name: "Search", StatusCheck: MockStatusChecker{"Search", CRITICAL, "down"}},
+		{Name: "Recommendations", StatusCheck: MockStatusChecker{"Recommendations", WARNING, "slow"}},
+	}
+
+	actual := WorstOf(nil, children...).CheckStatus("The optional dependencies").StatusList[0]
+	eDetails := "Worst of 2 checks is CRIT; CRIT: Search; WARN: Recommendations"
+	if actual.Result != CRITICAL || actual.Details != eDetails {
+		t.Errorf("Status should be `CRIT` `%s`, was `%v`", eDetails, actual)
+	}
+
+	actual = WorstOf(Downgrade{CRITICAL: WARNING}, children...).CheckStatus("The optional dependencies").StatusList[0]
+	eDetails = "Worst of 2 checks is WARN, downgraded from CRIT; CRIT: Search; WARN: Recommendations"
+	if actual.Result != WARNING || actual.Details != eDetails {
+		t.Errorf("Status should be `WARN` `%s`, was `%v`", eDetails, actual)
+	}
+
+	actual = WorstOf(Downgrade{CRITICAL: OK, WARNING: OK}, children...).CheckStatus("The optional dependencies").StatusList[0]
+	if actual.Result != OK {
+		t.Errorf("Result should be `OK`, was `%s`", actual.Result)
+	}
+}
+
+func TestCompositeInvalidResult(t *testing.T) {
+	children := []NamedCheck{
+		{Name: "Search", StatusCheck: MockStatusChecker{"Search", "WARNING", "typo"}},
+		{Name: "Recommendations", StatusCheck: MockStatusChecker{"Recommendations", OK, "all good"}},
+	}
+
+	actual := WorstOf(nil, children...).CheckStatus("The dependencies").StatusList[0]
+	eDetails := "Worst of 2 checks is CRIT; CRIT: Search"
+	if actual.Result != CRITICAL || actual.Details != eDetails {
+		t.Errorf("Status should be `CRIT` `%s`, was `%v`", eDetails, actual)
+	}
+
+	actual = AnyOf(children...).CheckStatus("The dependencies").StatusList[0]
+	eDetails = "1 of 2 checks are up, quorum of 1; CRIT: Search"
+	if actual.Result != WARNING || actual.Details != eDetails {
+		t.Errorf("Status should be `WARN` `%s`, was `%v`", eDetails, actual)
+	}
+}
+
+func TestCompositeEmptyChild(t *testing.T) {
+	s := AllOf(NamedCheck{Name: "Empty", StatusCheck: MockEmptyStatusChecker{}}).CheckStatus("The composite")
+
+	expected := Status{Description: "Empty", Result: CRITICAL, Details: "StatusList empty"}
+	if len(s.StatusList) != 2 || s.StatusList[1] != expected {
+		t.Errorf("StatusList should end with `%v`, was `%v`", expected, s.StatusList)
+	}
+}
+
+func TestCompositeConcurrency(t *testing.T) {
+	// Every child waits for all of them to start, which only completes if they run concurrently
+	var started sync.WaitGroup
+	started.Add(3)
+	children := []NamedCheck{}
+	for _, name := range []string{"a", "b", "c"} {
+		children = append(children, NamedCheck{Name: name, StatusCheck: MockBarrierStatusChecker{&started}})
+	}
+
+	done := make(chan StatusList)
+	go func() {
+		done <- AllOf(children...).CheckStatus("The composite")
+	}()
+
+	select {
+	case s := <-done:
+		if s.StatusList[0].Result != OK {
+			t.Errorf("Result should be `OK`, was `%s`", s.StatusList[0].Result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Children should run concurrently")
+	}
+}
+
+// Mocks
+
+type MockEmptyStatusChecker struct{}
+
+func (m MockEmptyStatusChecker) CheckStatus(name string) StatusList {
+	return StatusList{}
+}
+
+type MockBarrierStatusChecker struct {
+	started *sync.WaitGroup
+}
+
+func (m MockBarrierStatusChecker) CheckStatus(name string) StatusList {
+	m.started.Done()
+	m.started.Wait()
+	return StatusList{StatusList: []Status{{Description: name, Result: OK}}}
+}
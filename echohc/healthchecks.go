@@ -0,0 +1,39 @@
+package echohc
+
+import (
+	"strings"
+
+	"github.com/hootsuite/healthchecks"
+	"github.com/labstack/echo/v4"
+)
+
+// Router is implemented by both *echo.Echo and *echo.Group
+type Router interface {
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// HealthChecksEndpoints registers the status check routes on an echo instance or group under the given mount prefix, e.g. `/status`.
+//
+// `{prefix}/:slug` serves the V1 API and `{prefix}/v2/:slug` serves the V2 API for GET and HEAD requests.
+// POST requests to `{prefix}/v2/:slug` push the status of a healthchecks.PassiveCheck endpoint.
+func HealthChecksEndpoints(r Router, prefix string, statusEndpoints []healthchecks.StatusEndpoint, aboutFilePath string, versionFilePath string, customData map[string]interface{}) {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	v1 := endpointHandler(healthchecks.APIV1, statusEndpoints, aboutFilePath, versionFilePath, customData)
+	v2 := endpointHandler(healthchecks.APIV2, statusEndpoints, aboutFilePath, versionFilePath, customData)
+
+	r.GET(prefix+"/:slug", v1)
+	r.HEAD(prefix+"/:slug", v1)
+	r.GET(prefix+"/v2/:slug", v2)
+	r.HEAD(prefix+"/v2/:slug", v2)
+	r.POST(prefix+"/v2/:slug", v2)
+}
+
+func endpointHandler(apiVersion healthchecks.APIVersion, statusEndpoints []healthchecks.StatusEndpoint, aboutFilePath string, versionFilePath string, customData map[string]interface{}) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		healthchecks.ServeEndpoint(c.Response(), c.Request(), apiVersion, c.Param("slug"), statusEndpoints, aboutFilePath, versionFilePath, customData)
+		return nil
+	}
+}
@@ -0,0 +1,25 @@
+package echohc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hootsuite/healthchecks"
+	"github.com/hootsuite/healthchecks/internal/adaptertest"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHealthChecksEndpoints(t *testing.T) {
+	adaptertest.TestConformance(t, serve)
+}
+
+/* HELPER FUNCTIONS */
+func serve(prefix string, statusEndpoints []healthchecks.StatusEndpoint, req *http.Request) (int, string) {
+	r := echo.New()
+	HealthChecksEndpoints(r, prefix, statusEndpoints, "../test/about.json", "../test/version.txt", nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code, w.Body.String()
+}
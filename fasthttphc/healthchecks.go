@@ -0,0 +1,38 @@
+package fasthttphc
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/fasthttp/router"
+	"github.com/hootsuite/healthchecks"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// HealthChecksEndpoints registers the status check routes on a fasthttp router under the given mount prefix, e.g. `/status`.
+//
+// `{prefix}/{slug}` serves the V1 API and `{prefix}/v2/{slug}` serves the V2 API for GET and HEAD requests.
+// POST requests to `{prefix}/v2/{slug}` push the status of a healthchecks.PassiveCheck endpoint.
+func HealthChecksEndpoints(r *router.Router, prefix string, statusEndpoints []healthchecks.StatusEndpoint, aboutFilePath string, versionFilePath string, customData map[string]interface{}) {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	v1 := EndpointHandler(healthchecks.APIV1, statusEndpoints, aboutFilePath, versionFilePath, customData)
+	v2 := EndpointHandler(healthchecks.APIV2, statusEndpoints, aboutFilePath, versionFilePath, customData)
+
+	r.GET(prefix+"/{slug}", v1)
+	r.HEAD(prefix+"/{slug}", v1)
+	r.GET(prefix+"/v2/{slug}", v2)
+	r.HEAD(prefix+"/v2/{slug}", v2)
+	r.POST(prefix+"/v2/{slug}", v2)
+}
+
+// EndpointHandler returns a fasthttp.RequestHandler for the given API version that reads the endpoint from the
+// `slug` user value, for use with routers other than fasthttp/router.
+func EndpointHandler(apiVersion healthchecks.APIVersion, statusEndpoints []healthchecks.StatusEndpoint, aboutFilePath string, versionFilePath string, customData map[string]interface{}) fasthttp.RequestHandler {
+	// The context of the adapted request is the fasthttp.RequestCtx, whose values are its user values
+	return fasthttpadaptor.NewFastHTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slug, _ := r.Context().Value("slug").(string)
+		healthchecks.ServeEndpoint(w, r, apiVersion, slug, statusEndpoints, aboutFilePath, versionFilePath, customData)
+	})
+}
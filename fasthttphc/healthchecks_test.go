@@ -0,0 +1,38 @@
+package fasthttphc
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/fasthttp/router"
+	"github.com/hootsuite/healthchecks"
+	"github.com/hootsuite/healthchecks/internal/adaptertest"
+	"github.com/valyala/fasthttp"
+)
+
+func TestHealthChecksEndpoints(t *testing.T) {
+	adaptertest.TestConformance(t, serve)
+}
+
+/* HELPER FUNCTIONS */
+func serve(prefix string, statusEndpoints []healthchecks.StatusEndpoint, req *http.Request) (int, string) {
+	r := router.New()
+	HealthChecksEndpoints(r, prefix, statusEndpoints, "../test/about.json", "../test/version.txt", nil)
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(req.Method)
+	ctx.Request.SetRequestURI(req.URL.RequestURI())
+	for key, values := range req.Header {
+		for _, value := range values {
+			ctx.Request.Header.Add(key, value)
+		}
+	}
+	if req.Body != nil {
+		body, _ := ioutil.ReadAll(req.Body)
+		ctx.Request.SetBody(body)
+	}
+
+	r.Handler(ctx)
+	return ctx.Response.StatusCode(), string(ctx.Response.Body())
+}
@@ -0,0 +1,79 @@
+package healthchecks
+
+import (
+	"fmt"
+)
+
+// StatusCheckFunc is an adapter to use an ordinary function as a StatusCheck, like http.HandlerFunc
+type StatusCheckFunc func(name string) StatusList
+
+// CheckStatus calls f(name)
+func (f StatusCheckFunc) CheckStatus(name string) StatusList {
+	return f(name)
+}
+
+// TraverseCheckFunc is an adapter to use an ordinary function as a TraverseCheck
+type TraverseCheckFunc func(traversalPath []string, action string) (string, error)
+
+// Traverse calls f(traversalPath, action)
+func (f TraverseCheckFunc) Traverse(traversalPath []string, action string) (string, error) {
+	return f(traversalPath, action)
+}
+
+// OKStatus creates the StatusList of a successful check
+func OKStatus(name string) StatusList {
+	return singleStatus(name, OK, "")
+}
+
+// Warn creates the StatusList of a check raising a warning, its details formatted as fmt.Sprintf
+func Warn(name string, format string, a ...interface{}) StatusList {
+	return singleStatus(name, WARNING, fmt.Sprintf(format, a...))
+}
+
+// Crit creates the StatusList of a check failing with err
+func Crit(name string, err error) StatusList {
+	details := ""
+	if err != nil {
+		details = err.Error()
+	}
+	return singleStatus(name, CRITICAL, details)
+}
+
+// Severity ranks an alert level from OK (0) to WARN (1) and CRIT (2). Any other level, which Aggregate rejects, ranks
+// as CRIT.
+func Severity(result AlertLevel) int {
+	switch result {
+	case OK:
+		return 0
+	case WARNING:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Worst gets the most severe of the alert levels, OK if there are none. Any level other than OK or WARN is CRIT.
+func Worst(results ...AlertLevel) AlertLevel {
+	worst := OK
+	for _, result := range results {
+		switch Severity(result) {
+		case 2:
+			return CRITICAL
+		case 1:
+			worst = WARNING
+		}
+	}
+	return worst
+}
+
+func singleStatus(name string, result AlertLevel, details string) StatusList {
+	return StatusList{
+		StatusList: []Status{
+			{
+				Description: name,
+				Result:      result,
+				Details:     details,
+			},
+		},
+	}
+}
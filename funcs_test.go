@@ -0,0 +1,68 @@
+package healthchecks
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStatusCheckFunc(t *testing.T) {
+	var statusCheck StatusCheck = StatusCheckFunc(func(name string) StatusList {
+		return Warn(name, "%d of %d replicas are lagging", 1, 3)
+	})
+
+	actual := statusCheck.CheckStatus("The replicas").StatusList[0]
+	expected := Status{Description: "The replicas", Result: WARNING, Details: "1 of 3 replicas are lagging"}
+	if actual != expected {
+		t.Errorf("Status should be `%v`, was `%v`", expected, actual)
+	}
+}
+
+func TestTraverseCheckFunc(t *testing.T) {
+	var traverseCheck TraverseCheck = TraverseCheckFunc(func(traversalPath []string, action string) (string, error) {
+		return action + ":" + traversalPath[0], nil
+	})
+
+	actual, err := traverseCheck.Traverse([]string{"search"}, "about")
+	if err != nil || actual != "about:search" {
+		t.Errorf("Traverse should return `about:search`, was `%s` `%v`", actual, err)
+	}
+}
+
+func TestStatusHelpers(t *testing.T) {
+	expected := []struct {
+		actual   StatusList
+		expected Status
+	}{
+		{OKStatus("The cache"), Status{Description: "The cache", Result: OK, Details: ""}},
+		{Crit("The cache", errors.New("connection refused")), Status{Description: "The cache", Result: CRITICAL, Details: "connection refused"}},
+		{Crit("The cache", nil), Status{Description: "The cache", Result: CRITICAL, Details: ""}},
+		{Warn("The cache", "hit ratio of %.0f%%", 42.0), Status{Description: "The cache", Result: WARNING, Details: "hit ratio of 42%"}},
+	}
+	for _, e := range expected {
+		if len(e.actual.StatusList) != 1 || e.actual.StatusList[0] != e.expected {
+			t.Errorf("StatusList should be `%v`, was `%v`", e.expected, e.actual.StatusList)
+		}
+	}
+}
+
+func TestWorst(t *testing.T) {
+	expected := []struct {
+		results  []AlertLevel
+		expected AlertLevel
+	}{
+		{nil, OK},
+		{[]AlertLevel{OK, OK}, OK},
+		{[]AlertLevel{OK, WARNING, OK}, WARNING},
+		{[]AlertLevel{WARNING, CRITICAL, OK}, CRITICAL},
+		{[]AlertLevel{OK, "WARNING"}, CRITICAL},
+	}
+	for _, e := range expected {
+		if actual := Worst(e.results...); actual != e.expected {
+			t.Errorf("Worst of `%v` should be `%s`, was `%s`", e.results, e.expected, actual)
+		}
+	}
+
+	if Severity(OK) >= Severity(WARNING) || Severity(WARNING) >= Severity(CRITICAL) || Severity("") != Severity(CRITICAL) {
+		t.Errorf("Severity should rank OK, WARN then CRIT, and unknown levels as CRIT")
+	}
+}
@@ -0,0 +1,117 @@
+package grpchc
+
+import (
+	"context"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Default interval at which status checks are re-run for Watch streams
+const DefaultWatchInterval = 10 * time.Second
+
+// HealthServer implements the gRPC health checking protocol (grpc.health.v1.Health) on top of the
+// same StatusEndpoints used by the HTTP handlers.
+//
+// The service name of a request is the Slug of a StatusEndpoint. An empty service name checks the
+// aggregate status of all StatusEndpoints. OK and WARN results are reported as SERVING and CRIT
+// results as NOT_SERVING.
+type HealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+
+	StatusEndpoints []healthchecks.StatusEndpoint
+	WatchInterval   time.Duration // Optional interval at which Watch streams re-run the status check
+}
+
+// NewHealthServer returns a HealthServer for the given StatusEndpoints.
+func NewHealthServer(statusEndpoints []healthchecks.StatusEndpoint) *HealthServer {
+	return &HealthServer{
+		StatusEndpoints: statusEndpoints,
+		WatchInterval:   DefaultWatchInterval,
+	}
+}
+
+// Register registers a HealthServer for the given StatusEndpoints on a gRPC server.
+func Register(s *grpc.Server, statusEndpoints []healthchecks.StatusEndpoint) *HealthServer {
+	h := NewHealthServer(statusEndpoints)
+	grpc_health_v1.RegisterHealthServer(s, h)
+	return h
+}
+
+func (h *HealthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	servingStatus := h.servingStatus(req.GetService())
+	if servingStatus == grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN {
+		return nil, status.Errorf(codes.NotFound, "unknown service %s", req.GetService())
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: servingStatus}, nil
+}
+
+func (h *HealthServer) List(ctx context.Context, req *grpc_health_v1.HealthListRequest) (*grpc_health_v1.HealthListResponse, error) {
+	statuses := make(map[string]*grpc_health_v1.HealthCheckResponse, len(h.StatusEndpoints)+1)
+	statuses[""] = &grpc_health_v1.HealthCheckResponse{Status: h.servingStatus("")}
+	for _, s := range h.StatusEndpoints {
+		statuses[s.Slug] = &grpc_health_v1.HealthCheckResponse{Status: h.servingStatus(s.Slug)}
+	}
+
+	return &grpc_health_v1.HealthListResponse{Statuses: statuses}, nil
+}
+
+// Watch sends the current serving status right away and then re-runs the status check every
+// WatchInterval, sending a new message whenever the serving status changes.
+func (h *HealthServer) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	interval := h.WatchInterval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastStatus := grpc_health_v1.HealthCheckResponse_ServingStatus(-1)
+	for {
+		servingStatus := h.servingStatus(req.GetService())
+		if servingStatus != lastStatus {
+			if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: servingStatus}); err != nil {
+				return status.Error(codes.Canceled, "stream has ended")
+			}
+			lastStatus = servingStatus
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.Error(codes.Canceled, "stream has ended")
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *HealthServer) servingStatus(service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if service == "" {
+		return toServingStatus(healthchecks.AggregateStatusList(h.StatusEndpoints, ""))
+	}
+
+	s := healthchecks.FindStatusEndpoint(h.StatusEndpoints, service)
+	if s == nil {
+		return grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+
+	return toServingStatus(s.StatusCheck.CheckStatus(s.Name))
+}
+
+func toServingStatus(s healthchecks.StatusList) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if len(s.StatusList) == 0 {
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+
+	switch s.StatusList[0].Result {
+	case healthchecks.OK, healthchecks.WARNING:
+		return grpc_health_v1.HealthCheckResponse_SERVING
+	default:
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+}
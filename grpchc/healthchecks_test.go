@@ -0,0 +1,229 @@
+package grpchc
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hootsuite/healthchecks"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+/* VARIABLES */
+var testStatusEndpointA = healthchecks.StatusEndpoint{
+	Name:          "AAA",
+	Slug:          "aaa",
+	Type:          "internal",
+	IsTraversable: false,
+	StatusCheck:   MockStatusChecker{"AAA", healthchecks.OK, "all good"},
+	TraverseCheck: nil,
+}
+var testStatusEndpointB = healthchecks.StatusEndpoint{
+	Name:          "BBB",
+	Slug:          "bbb",
+	Type:          "internal",
+	IsTraversable: false,
+	StatusCheck:   MockStatusChecker{"BBB", healthchecks.WARNING, "degraded"},
+	TraverseCheck: nil,
+}
+var testStatusEndpointC = healthchecks.StatusEndpoint{
+	Name:          "CCC",
+	Slug:          "ccc",
+	Type:          "internal",
+	IsTraversable: false,
+	StatusCheck:   MockStatusChecker{"CCC", healthchecks.CRITICAL, "down"},
+	TraverseCheck: nil,
+}
+
+type MockStatusChecker struct {
+	Name    string
+	Result  healthchecks.AlertLevel
+	Details string
+}
+
+func (m MockStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	return healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: name,
+				Result:      m.Result,
+				Details:     m.Details,
+			},
+		},
+	}
+}
+
+// A status checker whose result can be changed while a test is running
+type SwitchableStatusChecker struct {
+	mu     sync.Mutex
+	result healthchecks.AlertLevel
+}
+
+func (s *SwitchableStatusChecker) Set(result healthchecks.AlertLevel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.result = result
+}
+
+func (s *SwitchableStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return healthchecks.StatusList{StatusList: []healthchecks.Status{{Description: name, Result: s.result}}}
+}
+
+/* TESTS */
+func TestCheck(t *testing.T) {
+	client, closeFunc := startServer(t, NewHealthServer([]healthchecks.StatusEndpoint{
+		testStatusEndpointA,
+		testStatusEndpointB,
+		testStatusEndpointC,
+	}))
+	defer closeFunc()
+
+	tests := map[string]grpc_health_v1.HealthCheckResponse_ServingStatus{
+		"aaa": grpc_health_v1.HealthCheckResponse_SERVING,
+		"bbb": grpc_health_v1.HealthCheckResponse_SERVING,
+		"ccc": grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		"":    grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+	}
+
+	for service, expected := range tests {
+		resp, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("Check of `%s` returned an error: %s", service, err)
+		}
+		if resp.GetStatus() != expected {
+			t.Errorf("Status of `%s` should be `%s`, was `%s`", service, expected, resp.GetStatus())
+		}
+	}
+}
+
+func TestCheckAggregateServing(t *testing.T) {
+	client, closeFunc := startServer(t, NewHealthServer([]healthchecks.StatusEndpoint{
+		testStatusEndpointA,
+		testStatusEndpointB,
+	}))
+	defer closeFunc()
+
+	resp, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Check returned an error: %s", err)
+	}
+	if resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Errorf("Status should be `SERVING`, was `%s`", resp.GetStatus())
+	}
+}
+
+func TestCheckUnknownService(t *testing.T) {
+	client, closeFunc := startServer(t, NewHealthServer([]healthchecks.StatusEndpoint{testStatusEndpointA}))
+	defer closeFunc()
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "zzz"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Error code should be `NotFound`, was `%s`", status.Code(err))
+	}
+}
+
+func TestList(t *testing.T) {
+	client, closeFunc := startServer(t, NewHealthServer([]healthchecks.StatusEndpoint{
+		testStatusEndpointA,
+		testStatusEndpointC,
+	}))
+	defer closeFunc()
+
+	resp, err := client.List(context.Background(), &grpc_health_v1.HealthListRequest{})
+	if err != nil {
+		t.Fatalf("List returned an error: %s", err)
+	}
+
+	statuses := resp.GetStatuses()
+	if len(statuses) != 3 {
+		t.Errorf("List should return 3 statuses, returned %d", len(statuses))
+	}
+	if statuses["aaa"].GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Errorf("Status of `aaa` should be `SERVING`, was `%s`", statuses["aaa"].GetStatus())
+	}
+	if statuses["ccc"].GetStatus() != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Status of `ccc` should be `NOT_SERVING`, was `%s`", statuses["ccc"].GetStatus())
+	}
+}
+
+func TestWatch(t *testing.T) {
+	checker := &SwitchableStatusChecker{result: healthchecks.OK}
+	server := NewHealthServer([]healthchecks.StatusEndpoint{
+		{Name: "Switchable", Slug: "switchable", Type: "internal", StatusCheck: checker},
+	})
+	server.WatchInterval = 5 * time.Millisecond
+
+	client, closeFunc := startServer(t, server)
+	defer closeFunc()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: "switchable"})
+	if err != nil {
+		t.Fatalf("Watch returned an error: %s", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil || resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Fatalf("First status should be `SERVING`, was `%s` (error: %v)", resp.GetStatus(), err)
+	}
+
+	checker.Set(healthchecks.CRITICAL)
+
+	resp, err = stream.Recv()
+	if err != nil || resp.GetStatus() != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("Second status should be `NOT_SERVING`, was `%s` (error: %v)", resp.GetStatus(), err)
+	}
+}
+
+func TestWatchUnknownService(t *testing.T) {
+	client, closeFunc := startServer(t, NewHealthServer([]healthchecks.StatusEndpoint{testStatusEndpointA}))
+	defer closeFunc()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: "zzz"})
+	if err != nil {
+		t.Fatalf("Watch returned an error: %s", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil || resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN {
+		t.Errorf("Status should be `SERVICE_UNKNOWN`, was `%s` (error: %v)", resp.GetStatus(), err)
+	}
+}
+
+/* HELPER FUNCTIONS */
+func startServer(t *testing.T, h *HealthServer) (grpc_health_v1.HealthClient, func()) {
+	listener := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(s, h)
+	go s.Serve(listener)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Error dialing bufconn server: %s", err)
+	}
+
+	return grpc_health_v1.NewHealthClient(conn), func() {
+		conn.Close()
+		s.Stop()
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"strings"
 )
 
+// DefaultPathPrefix is the path the status check handlers are mounted at by Handler and HandlerFunc
+const DefaultPathPrefix = "/status"
+
 // Handler returns a http.Handler that responds to status check requests. It should be registered at `/status/...`
 func Handler(statusEndpoints []StatusEndpoint, aboutFilePath string, versionFilePath string, customData map[string]interface{}) http.Handler {
 	return HandlerFunc(statusEndpoints, aboutFilePath, versionFilePath, customData)
@@ -15,23 +18,126 @@ func Handler(statusEndpoints []StatusEndpoint, aboutFilePath string, versionFile
 
 // HandlerFunc returns a http.HandlerFunc that responds to status check requests. It should be registered at `/status/...`
 func HandlerFunc(statusEndpoints []StatusEndpoint, aboutFilePath string, versionFilePath string, customData map[string]interface{}) http.HandlerFunc {
+	return PrefixHandlerFunc(DefaultPathPrefix, statusEndpoints, aboutFilePath, versionFilePath, customData)
+}
+
+// PrefixHandler returns a http.Handler that responds to status check requests. It should be registered at `{prefix}/...`
+func PrefixHandler(prefix string, statusEndpoints []StatusEndpoint, aboutFilePath string, versionFilePath string, customData map[string]interface{}) http.Handler {
+	return PrefixHandlerFunc(prefix, statusEndpoints, aboutFilePath, versionFilePath, customData)
+}
+
+// PrefixHandlerFunc returns a http.HandlerFunc that responds to status check requests. It should be registered at `{prefix}/...`
+//
+// `{prefix}/{endpoint}` is routed to the V1 API and `{prefix}/v2/{endpoint}` to the V2 API, a trailing slash is ignored.
+// Any other path results in a 404 response and any method other than GET or HEAD in a 405 response, except POST to
+// the V2 endpoint of a PassiveCheck.
+func PrefixHandlerFunc(prefix string, statusEndpoints []StatusEndpoint, aboutFilePath string, versionFilePath string, customData map[string]interface{}) http.HandlerFunc {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix != "" && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		slug := strings.Split(r.URL.Path, "/")
+		apiVersion, endpoint, ok := routeStatusPath(prefix, r.URL.Path)
 
-		apiVersion := APIV1
-		if strings.ToLower(slug[2]) == "v2" {
-			apiVersion = APIV2
+		if r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodPost {
+			w.Header().Set("Allow", allowedMethods(apiVersion, endpoint, statusEndpoints))
+			writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed", fmt.Sprintf("Method %s is not allowed", r.Method), apiVersion)
+			return
 		}
 
-		switch apiVersion {
-		case APIV1:
-			handleV1Api(w, r, slug[2], statusEndpoints, aboutFilePath, versionFilePath, customData)
-		case APIV2:
-			handleV2Api(w, r, slug[3], statusEndpoints, aboutFilePath, versionFilePath, customData)
+		if !ok {
+			writeErrorResponse(w, http.StatusNotFound, "Unknown Status endpoint", fmt.Sprintf("Status endpoint does not exist: %s", r.URL.Path), apiVersion)
+			return
 		}
+
+		ServeEndpoint(w, r, apiVersion, endpoint, statusEndpoints, aboutFilePath, versionFilePath, customData)
 	})
 }
 
+// Extract the API version and the endpoint from a request path of the form `{prefix}/{endpoint}` or
+// `{prefix}/v2/{endpoint}`. Returns false if the path does not match either form.
+func routeStatusPath(prefix string, path string) (APIVersion, string, bool) {
+	if !strings.HasPrefix(path, prefix+"/") {
+		return APIV1, "", false
+	}
+
+	segments := strings.Split(strings.TrimSuffix(path[len(prefix)+1:], "/"), "/")
+
+	switch {
+	case len(segments) == 1 && segments[0] != "" && !strings.EqualFold(segments[0], "v2"):
+		return APIV1, segments[0], true
+	case len(segments) == 2 && strings.EqualFold(segments[0], "v2") && segments[1] != "":
+		return APIV2, segments[1], true
+	case len(segments) > 0 && strings.EqualFold(segments[0], "v2"):
+		return APIV2, "", false
+	default:
+		return APIV1, "", false
+	}
+}
+
+// Get the methods allowed on an endpoint for the Allow header, POST being allowed on the V2 endpoint of a PassiveCheck
+func allowedMethods(apiVersion APIVersion, endpoint string, statusEndpoints []StatusEndpoint) string {
+	if apiVersion == APIV2 && passiveCheck(FindStatusEndpoint(statusEndpoints, endpoint)) != nil {
+		return "GET, HEAD, POST"
+	}
+	return "GET, HEAD"
+}
+
+func writeErrorResponse(w http.ResponseWriter, statusCode int, description string, details string, apiVersion APIVersion) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	io.WriteString(w, SerializeStatusList(StatusList{
+		StatusList: []Status{
+			{
+				Description: description,
+				Result:      CRITICAL,
+				Details:     details,
+			},
+		},
+	}, apiVersion))
+}
+
+// ServeEndpoint responds to the status check request for a single endpoint (`about`, `aggregate`, `am-i-up`,
+// `traverse` or the slug of a StatusEndpoint) of the given API version. It is meant for routers that extract the
+// API version and endpoint from the request path themselves, e.g. as route parameters.
+//
+// `aggregate` and the StatusEndpoint slugs are rendered as the text/plain output of a Nagios plugin with the
+// `format=nagios` query parameter.
+//
+// A POST request pushes the status of a PassiveCheck endpoint of the V2 API and results in a 405 response for any
+// other endpoint.
+func ServeEndpoint(
+	w http.ResponseWriter,
+	r *http.Request,
+	apiVersion APIVersion,
+	endpoint string,
+	statusEndpoints []StatusEndpoint,
+	aboutFilePath string,
+	versionFilePath string,
+	customData map[string]interface{},
+) {
+	if r.Method == http.MethodPost {
+		statusEndpoint := FindStatusEndpoint(statusEndpoints, endpoint)
+		p := passiveCheck(statusEndpoint)
+		if apiVersion != APIV2 || p == nil {
+			w.Header().Set("Allow", allowedMethods(apiVersion, endpoint, statusEndpoints))
+			writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed", fmt.Sprintf("Method %s is not allowed", r.Method), apiVersion)
+			return
+		}
+
+		servePassivePush(w, r, statusEndpoint, p)
+		return
+	}
+
+	switch apiVersion {
+	case APIV2:
+		handleV2Api(w, r, endpoint, statusEndpoints, aboutFilePath, versionFilePath, customData)
+	default:
+		handleV1Api(w, r, endpoint, statusEndpoints, aboutFilePath, versionFilePath, customData)
+	}
+}
+
 func handleV1Api(
 	w http.ResponseWriter,
 	r *http.Request,
@@ -48,6 +154,10 @@ func handleV1Api(
 		io.WriteString(w, aboutResp)
 	case "aggregate":
 		typeFilter := r.URL.Query().Get("type")
+		if r.URL.Query().Get("format") == "nagios" {
+			writeNagiosResponse(w, NagiosAggregate(statusEndpoints, typeFilter))
+			return
+		}
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
 		io.WriteString(w, Aggregate(statusEndpoints, typeFilter, APIV1))
 	case "am-i-up":
@@ -69,20 +179,14 @@ func handleV1Api(
 	default:
 		endpoint := FindStatusEndpoint(statusEndpoints, endpoint)
 		if endpoint == nil {
-			w.Header().Set("Content-Type", "application/json; charset=utf-8")
-			w.WriteHeader(http.StatusNotFound)
-			io.WriteString(w, SerializeStatusList(StatusList{
-				StatusList: []Status{
-					{
-						Description: "Unknown Status endpoint",
-						Result:      CRITICAL,
-						Details:     fmt.Sprintf("Status endpoint does not exist: %s", r.URL.Path),
-					},
-				},
-			}, APIV1))
+			writeErrorResponse(w, http.StatusNotFound, "Unknown Status endpoint", fmt.Sprintf("Status endpoint does not exist: %s", r.URL.Path), APIV1)
 			return
 		}
 
+		if r.URL.Query().Get("format") == "nagios" {
+			writeNagiosResponse(w, NagiosStatusCheck(endpoint))
+			return
+		}
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
 		io.WriteString(w, ExecuteStatusCheck(endpoint, APIV1))
 	}
@@ -109,6 +213,10 @@ func handleV2Api(
 		io.WriteString(w, aboutResp)
 	case "aggregate":
 		typeFilter := r.URL.Query().Get("type")
+		if r.URL.Query().Get("format") == "nagios" {
+			writeNagiosResponse(w, NagiosAggregate(statusEndpoints, typeFilter))
+			return
+		}
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
 		io.WriteString(w, Aggregate(statusEndpoints, typeFilter, APIV2))
 	case "am-i-up":
@@ -131,20 +239,14 @@ func handleV2Api(
 	default:
 		endpoint := FindStatusEndpoint(statusEndpoints, endpoint)
 		if endpoint == nil {
-			w.Header().Set("Content-Type", "application/json; charset=utf-8")
-			w.WriteHeader(http.StatusNotFound)
-			io.WriteString(w, SerializeStatusList(StatusList{
-				StatusList: []Status{
-					{
-						Description: "Unknown Status endpoint",
-						Result:      CRITICAL,
-						Details:     fmt.Sprintf("Status endpoint does not exist: %s", r.URL.Path),
-					},
-				},
-			}, APIV2))
+			writeErrorResponse(w, http.StatusNotFound, "Unknown Status endpoint", fmt.Sprintf("Status endpoint does not exist: %s", r.URL.Path), APIV2)
 			return
 		}
 
+		if r.URL.Query().Get("format") == "nagios" {
+			writeNagiosResponse(w, NagiosStatusCheck(endpoint))
+			return
+		}
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
 		io.WriteString(w, ExecuteStatusCheck(endpoint, APIV2))
 	}
@@ -150,6 +150,126 @@ func TestHttpTraverse(t *testing.T) {
 	assertSuccessfulJSONResponse(t, w)
 }
 
+func TestServeEndpoint(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/anywhere/else", nil)
+	w := httptest.NewRecorder()
+
+	ServeEndpoint(w, req, APIV2, "aaa", testStatusEndpoints, "test/about.json", "test/version.txt", nil)
+
+	assertSuccessfulJSONResponse(t, w)
+	assertBody(`{"description":"AAA","result":"OK","details":"all good"}`, t, w)
+}
+
+func TestServeEndpointUnknown(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/anywhere/else", nil)
+	w := httptest.NewRecorder()
+
+	ServeEndpoint(w, req, APIV1, "something", testStatusEndpoints, "test/about.json", "test/version.txt", nil)
+
+	assertStatusCode(http.StatusNotFound, t, w)
+	assertContentTypeHeader("application/json; charset=utf-8", t, w)
+}
+
+func TestHttpMountPrefix(t *testing.T) {
+	prefixHandler := PrefixHandler("/internal/status/", testStatusEndpoints, "test/about.json", "test/version.txt", nil)
+
+	req, _ := http.NewRequest("GET", "/internal/status/v2/aaa", nil)
+	w := httptest.NewRecorder()
+
+	prefixHandler.ServeHTTP(w, req)
+
+	assertSuccessfulJSONResponse(t, w)
+	assertBody(`{"description":"AAA","result":"OK","details":"all good"}`, t, w)
+
+	req, _ = http.NewRequest("GET", "/status/v2/aaa", nil)
+	w = httptest.NewRecorder()
+
+	prefixHandler.ServeHTTP(w, req)
+
+	assertStatusCode(http.StatusNotFound, t, w)
+}
+
+func TestHttpRootMountPrefix(t *testing.T) {
+	prefixHandler := PrefixHandler("", testStatusEndpoints, "test/about.json", "test/version.txt", nil)
+
+	req, _ := http.NewRequest("GET", "/aaa", nil)
+	w := httptest.NewRecorder()
+
+	prefixHandler.ServeHTTP(w, req)
+
+	assertSuccessfulJSONResponse(t, w)
+	assertBody(`["OK"]`, t, w)
+}
+
+func TestHttpTrailingSlash(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/status/v2/aaa/", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assertSuccessfulJSONResponse(t, w)
+	assertBody(`{"description":"AAA","result":"OK","details":"all good"}`, t, w)
+}
+
+func TestHttpMalformedPaths(t *testing.T) {
+	paths := []string{
+		"/status",
+		"/status/",
+		"/status/v2",
+		"/status/v2/",
+		"/status/V2",
+		"/status/aaa/bbb",
+		"/status/v2/aaa/bbb",
+		"/status//aaa",
+		"/statusaaa",
+		"/other/aaa",
+	}
+
+	for _, path := range paths {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Status code of `%s` should be `%d`, was: %d", path, http.StatusNotFound, w.Code)
+		}
+		assertContentTypeHeader("application/json; charset=utf-8", t, w)
+	}
+}
+
+func TestHttpMalformedPathV2Response(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/status/v2", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assertStatusCode(http.StatusNotFound, t, w)
+	assertBody(`{"description":"Unknown Status endpoint","result":"CRIT","details":"Status endpoint does not exist: /status/v2"}`, t, w)
+}
+
+func TestHttpMethodNotAllowed(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/status/v2/aaa", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assertStatusCode(http.StatusMethodNotAllowed, t, w)
+	assertContentTypeHeader("application/json; charset=utf-8", t, w)
+	if w.Header().Get("Allow") != "GET, HEAD" {
+		t.Errorf("Allow header should be `GET, HEAD`, was: %s", w.Header().Get("Allow"))
+	}
+}
+
+func TestHttpHead(t *testing.T) {
+	req, _ := http.NewRequest("HEAD", "/status/am-i-up", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assertStatusCode(http.StatusOK, t, w)
+}
+
 /* HELPER FUNCTIONS */
 func assertSuccessfulJSONResponse(t *testing.T, w *httptest.ResponseRecorder) {
 	assertStatusCode(http.StatusOK, t, w)
@@ -0,0 +1,99 @@
+// Package adaptertest holds the conformance tests shared by the router adapters of the healthchecks API, such as
+// chihc, echohc, muxhc and fasthttphc.
+package adaptertest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hootsuite/healthchecks"
+)
+
+// ServeFunc registers the status check routes of an adapter under prefix on a new router, serves req and returns the
+// response status code and body
+type ServeFunc func(prefix string, statusEndpoints []healthchecks.StatusEndpoint, req *http.Request) (int, string)
+
+func testStatusEndpoints() []healthchecks.StatusEndpoint {
+	backup := &healthchecks.PassiveCheck{Token: "secret"}
+	backup.Set(healthchecks.Status{Result: healthchecks.OK, Details: "Backup started"}, 0)
+
+	return []healthchecks.StatusEndpoint{
+		{
+			Name:          "AAA",
+			Slug:          "aaa",
+			Type:          "internal",
+			IsTraversable: false,
+			StatusCheck:   MockStatusChecker{"AAA", healthchecks.OK, "all good"},
+			TraverseCheck: nil,
+		},
+		{
+			Name:          "Backup",
+			Slug:          "backup",
+			Type:          "internal",
+			IsTraversable: false,
+			StatusCheck:   backup,
+			TraverseCheck: nil,
+		},
+	}
+}
+
+// TestConformance checks that the routes of an adapter serve the healthchecks API
+func TestConformance(t *testing.T, serve ServeFunc) {
+	expected := []struct {
+		name       string
+		prefix     string
+		method     string
+		path       string
+		token      string
+		push       string
+		statusCode int
+		body       string
+	}{
+		{"am-i-up", "/status", "GET", "/status/am-i-up", "", "", http.StatusOK, "OK"},
+		{"status endpoint V2", "/status", "GET", "/status/v2/aaa", "", "", http.StatusOK, `{"description":"AAA","result":"OK","details":"all good"}`},
+		{"aggregate V1", "/status", "GET", "/status/aggregate", "", "", http.StatusOK, `["OK"]`},
+		{"mount prefix", "/internal/status/", "GET", "/internal/status/v2/aaa", "", "", http.StatusOK, `{"description":"AAA","result":"OK","details":"all good"}`},
+		{"unknown endpoint", "/status", "GET", "/status/v2/zzz", "", "", http.StatusNotFound, ""},
+		{"method not allowed", "/status", "POST", "/status/aaa", "", "", http.StatusMethodNotAllowed, ""},
+		{"passive push", "/status", "POST", "/status/v2/backup", "secret", `{"result":"OK","details":"Backup completed"}`, http.StatusOK, `{"description":"Backup","result":"OK","details":"Backup completed"}`},
+		{"passive push unauthorized", "/status", "POST", "/status/v2/backup", "wrong", `{"result":"OK","details":"Backup completed"}`, http.StatusUnauthorized, ""},
+		{"passive push V1", "/status", "POST", "/status/backup", "secret", `{"result":"OK","details":"Backup completed"}`, http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, e := range expected {
+		t.Run(e.name, func(t *testing.T) {
+			req, _ := http.NewRequest(e.method, e.path, strings.NewReader(e.push))
+			if e.token != "" {
+				req.Header.Set("Authorization", "Bearer "+e.token)
+			}
+			statusCode, body := serve(e.prefix, testStatusEndpoints(), req)
+
+			if statusCode != e.statusCode {
+				t.Errorf("Status code should be `%d`, was: %d", e.statusCode, statusCode)
+			}
+			if e.body != "" && strings.TrimSpace(body) != e.body {
+				t.Errorf("Response body should be `%s`, was: `%s`", e.body, strings.TrimSpace(body))
+			}
+		})
+	}
+}
+
+// MockStatusChecker is a StatusCheck returning a fixed status
+type MockStatusChecker struct {
+	Name    string
+	Result  healthchecks.AlertLevel
+	Details string
+}
+
+func (m MockStatusChecker) CheckStatus(name string) healthchecks.StatusList {
+	return healthchecks.StatusList{
+		StatusList: []healthchecks.Status{
+			{
+				Description: name,
+				Result:      m.Result,
+				Details:     m.Details,
+			},
+		},
+	}
+}
@@ -0,0 +1,63 @@
+// Package canary holds the timed write, read and delete round trip shared by the canary checkers of checks/sqlsc and
+// checks/redissc.
+package canary
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Values generates a random, time ordered key and a random value for a canary
+func Values() (string, string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("%d-%s", time.Now().UnixNano(), hex.EncodeToString(b[:8])), hex.EncodeToString(b[8:]), nil
+}
+
+// RoundTrip writes a canary, reads it back and verifies it holds value, then deletes it again. The delete step always
+// runs once the write succeeded, so a failed read does not leave the canary behind.
+//
+// It returns the total latency of the steps, the latency of each step, e.g. `write=1ms read=2ms delete=1ms`, and the
+// error of the first failed step.
+func RoundTrip(value string, write func() error, read func() (string, error), del func() error) (time.Duration, string, error) {
+	latencies := []string{}
+	var total time.Duration
+	step := func(stepName string, f func() error) error {
+		start := time.Now()
+		err := f()
+		elapsed := time.Since(start)
+		total += elapsed
+		latencies = append(latencies, fmt.Sprintf("%s=%s", stepName, elapsed))
+		if err != nil {
+			return fmt.Errorf("%s failed: %v", stepName, err)
+		}
+		return nil
+	}
+
+	err := step("write", write)
+
+	if err == nil {
+		err = step("read", func() error {
+			actual, err := read()
+			if err != nil {
+				return err
+			}
+			if actual != value {
+				return fmt.Errorf("expected value `%s`, got `%s`", value, actual)
+			}
+			return nil
+		})
+
+		deleteErr := step("delete", del)
+		if err == nil {
+			err = deleteErr
+		}
+	}
+
+	return total, strings.Join(latencies, " "), err
+}
@@ -0,0 +1,19 @@
+// Package units formats the quantities reported in the details of the checkers.
+package units
+
+import "fmt"
+
+// FormatBytes formats a number of bytes with a binary unit, e.g. `512B` or `1.5GiB`
+func FormatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
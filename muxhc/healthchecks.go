@@ -0,0 +1,28 @@
+package muxhc
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/hootsuite/healthchecks"
+)
+
+// HealthChecksEndpoints registers the status check routes on a gorilla/mux router under the given mount prefix, e.g. `/status`.
+//
+// `{prefix}/{slug}` serves the V1 API and `{prefix}/v2/{slug}` serves the V2 API for GET and HEAD requests.
+// POST requests to `{prefix}/v2/{slug}` push the status of a healthchecks.PassiveCheck endpoint.
+func HealthChecksEndpoints(r *mux.Router, prefix string, statusEndpoints []healthchecks.StatusEndpoint, aboutFilePath string, versionFilePath string, customData map[string]interface{}) {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	r.HandleFunc(prefix+"/v2/{slug}", endpointHandler(healthchecks.APIV2, statusEndpoints, aboutFilePath, versionFilePath, customData)).
+		Methods(http.MethodGet, http.MethodHead, http.MethodPost)
+	r.HandleFunc(prefix+"/{slug}", endpointHandler(healthchecks.APIV1, statusEndpoints, aboutFilePath, versionFilePath, customData)).
+		Methods(http.MethodGet, http.MethodHead)
+}
+
+func endpointHandler(apiVersion healthchecks.APIVersion, statusEndpoints []healthchecks.StatusEndpoint, aboutFilePath string, versionFilePath string, customData map[string]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		healthchecks.ServeEndpoint(w, r, apiVersion, mux.Vars(r)["slug"], statusEndpoints, aboutFilePath, versionFilePath, customData)
+	}
+}
@@ -0,0 +1,25 @@
+package muxhc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hootsuite/healthchecks"
+	"github.com/hootsuite/healthchecks/internal/adaptertest"
+)
+
+func TestHealthChecksEndpoints(t *testing.T) {
+	adaptertest.TestConformance(t, serve)
+}
+
+/* HELPER FUNCTIONS */
+func serve(prefix string, statusEndpoints []healthchecks.StatusEndpoint, req *http.Request) (int, string) {
+	r := mux.NewRouter()
+	HealthChecksEndpoints(r, prefix, statusEndpoints, "../test/about.json", "../test/version.txt", nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code, w.Body.String()
+}
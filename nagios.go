@@ -0,0 +1,134 @@
+package healthchecks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NagiosPerfdata is a performance data metric of a Nagios plugin output
+type NagiosPerfdata struct {
+	Label string
+	Value float64
+	UOM   string // Unit of measurement, e.g. `s`
+}
+
+// String renders the metric as `'label'=valueUOM`
+func (p NagiosPerfdata) String() string {
+	return fmt.Sprintf("'%s'=%s%s", strings.Replace(p.Label, "'", "''", -1), strconv.FormatFloat(p.Value, 'f', -1, 64), p.UOM)
+}
+
+// NagiosState gets the Nagios service state of an alert level: OK, WARNING, CRITICAL or UNKNOWN
+func NagiosState(result AlertLevel) string {
+	switch result {
+	case OK:
+		return "OK"
+	case WARNING:
+		return "WARNING"
+	case CRITICAL:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// NagiosExitCode gets the exit code of a Nagios plugin reporting an alert level: 0, 1, 2 or 3 if unknown
+func NagiosExitCode(result AlertLevel) int {
+	switch result {
+	case OK:
+		return 0
+	case WARNING:
+		return 1
+	case CRITICAL:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// FormatNagios renders a StatusList as the output of a Nagios plugin. The first line holds the first status and the
+// performance data, `SERVICE OK - details | 'label'=0.012s`, followed by one line per other status,
+// `[WARN] description: details`.
+func FormatNagios(s StatusList, perfdata []NagiosPerfdata) string {
+	if len(s.StatusList) == 0 {
+		s = StatusList{
+			StatusList: []Status{
+				{
+					Description: "Invalid status response",
+					Result:      CRITICAL,
+					Details:     "StatusList empty",
+				},
+			},
+		}
+	}
+
+	first := s.StatusList[0]
+	details := first.Details
+	if details == "" {
+		details = first.Description
+	}
+	line := fmt.Sprintf("SERVICE %s - %s", NagiosState(first.Result), nagiosText(details))
+
+	if len(perfdata) > 0 {
+		metrics := make([]string, 0, len(perfdata))
+		for _, p := range perfdata {
+			metrics = append(metrics, p.String())
+		}
+		line += " | " + strings.Join(metrics, " ")
+	}
+
+	lines := []string{line}
+	for _, status := range s.StatusList[1:] {
+		lines = append(lines, nagiosText(fmt.Sprintf("[%s] %s: %s", status.Result, status.Description, status.Details)))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Keep text on a single line and out of the performance data
+func nagiosText(text string) string {
+	return strings.NewReplacer("|", "/", "\r", " ", "\n", " ").Replace(text)
+}
+
+// NagiosStatusCheck executes the StatusCheck of an endpoint and renders it as the output of a Nagios plugin, with its
+// StatusDuration as performance data
+func NagiosStatusCheck(s *StatusEndpoint) string {
+	start := time.Now()
+	statusList := s.StatusCheck.CheckStatus(s.Name)
+	elapsed := time.Since(start).Seconds()
+
+	return FormatNagios(statusList, []NagiosPerfdata{{Label: s.Slug, Value: elapsed, UOM: "s"}})
+}
+
+// NagiosAggregate executes the StatusCheck of every endpoint asynchronously and renders the overall status of
+// AggregateStatusList as the output of a Nagios plugin. The first line holds the overall status and the
+// StatusDuration of every endpoint as performance data, followed by one line per endpoint.
+func NagiosAggregate(statusEndpoints []StatusEndpoint, typeFilter string) string {
+	filtered, ok := filterStatusEndpoints(statusEndpoints, typeFilter)
+	if !ok {
+		return FormatNagios(invalidTypeStatusList(), nil)
+	}
+
+	endpointStatuses := make([]Status, len(filtered))
+	perfdata := make([]NagiosPerfdata, len(filtered))
+
+	overall := aggregateStatusList(filtered, func(result endpointResult) {
+		statusEndpoint := filtered[result.index]
+		status := Status{Description: statusEndpoint.Name, Result: CRITICAL, Details: "StatusList empty"}
+		if len(result.statusList.StatusList) > 0 {
+			status = result.statusList.StatusList[0]
+		}
+		endpointStatuses[result.index] = status
+		perfdata[result.index] = NagiosPerfdata{Label: statusEndpoint.Slug, Value: result.duration.Seconds(), UOM: "s"}
+	})
+
+	return FormatNagios(StatusList{StatusList: append([]Status{overall.StatusList[0]}, endpointStatuses...)}, perfdata)
+}
+
+func writeNagiosResponse(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, body)
+}
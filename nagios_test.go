@@ -0,0 +1,101 @@
+package healthchecks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestFormatNagios(t *testing.T) {
+	s := StatusList{
+		StatusList: []Status{
+			{Description: "Redis Cluster", Result: WARNING, Details: "1 node is down | failing over"},
+			{Description: "10.0.0.1:6379", Result: OK, Details: "role=master"},
+			{Description: "10.0.0.2:6379", Result: CRITICAL, Details: "connection refused\nretrying"},
+		},
+	}
+	actual := FormatNagios(s, []NagiosPerfdata{{Label: "redis", Value: 0.0125, UOM: "s"}, {Label: "it's", Value: 3}})
+
+	expected := "SERVICE WARNING - 1 node is down / failing over | 'redis'=0.0125s 'it''s'=3\n" +
+		"[OK] 10.0.0.1:6379: role=master\n" +
+		"[CRIT] 10.0.0.2:6379: connection refused retrying\n"
+	if actual != expected {
+		t.Errorf("Nagios output should be `%s`, was `%s`", expected, actual)
+	}
+}
+
+func TestFormatNagiosEmpty(t *testing.T) {
+	actual := FormatNagios(StatusList{}, nil)
+
+	expected := "SERVICE CRITICAL - StatusList empty\n"
+	if actual != expected {
+		t.Errorf("Nagios output should be `%s`, was `%s`", expected, actual)
+	}
+}
+
+func TestNagiosExitCode(t *testing.T) {
+	expected := map[AlertLevel]int{OK: 0, WARNING: 1, CRITICAL: 2, "UNKNOWN": 3}
+	for result, exitCode := range expected {
+		if NagiosExitCode(result) != exitCode {
+			t.Errorf("Exit code of `%s` should be %d, was %d", result, exitCode, NagiosExitCode(result))
+		}
+	}
+}
+
+func TestNagiosAggregate(t *testing.T) {
+	statusEndpoints := []StatusEndpoint{
+		{Name: "AAA", Slug: "aaa", Type: "internal", StatusCheck: MockStatusChecker{"AAA", OK, "all good"}},
+		{Name: "BBB", Slug: "bbb", Type: "external", StatusCheck: MockStatusChecker{"BBB", WARNING, "slow"}},
+		{Name: "CCC", Slug: "ccc", Type: "external", StatusCheck: MockStatusChecker{"CCC", CRITICAL, "down"}},
+		{Name: "DDD", Slug: "ddd", Type: "external", StatusCheck: MockStatusChecker{"DDD", CRITICAL, "also down"}},
+	}
+
+	actual := NagiosAggregate(statusEndpoints, "")
+	expected := regexp.MustCompile(`^SERVICE CRITICAL - (also )?down \| 'aaa'=[0-9.e-]+s 'bbb'=[0-9.e-]+s 'ccc'=[0-9.e-]+s 'ddd'=[0-9.e-]+s\n` +
+		`\[OK\] AAA: all good\n\[WARN\] BBB: slow\n\[CRIT\] CCC: down\n\[CRIT\] DDD: also down\n$`)
+	if !expected.MatchString(actual) {
+		t.Errorf("Nagios output should match `%s`, was `%s`", expected, actual)
+	}
+
+	actual = NagiosAggregate(statusEndpoints, "internal")
+	expected = regexp.MustCompile(`^SERVICE OK - All checks are OK \| 'aaa'=[0-9.e-]+s\n\[OK\] AAA: all good\n$`)
+	if !expected.MatchString(actual) {
+		t.Errorf("Nagios output should match `%s`, was `%s`", expected, actual)
+	}
+
+	actual = NagiosAggregate(statusEndpoints, "unknown")
+	if actual != "SERVICE CRITICAL - Unknown check type given for aggregate check\n" {
+		t.Errorf("Nagios output should be the invalid type, was `%s`", actual)
+	}
+}
+
+func TestHttpNagios(t *testing.T) {
+	for _, path := range []string{"/status/aaa?format=nagios", "/status/v2/aaa?format=nagios"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assertStatusCode(http.StatusOK, t, w)
+		assertContentTypeHeader("text/plain; charset=utf-8", t, w)
+		expected := regexp.MustCompile(`^SERVICE OK - all good \| 'aaa'=[0-9.e-]+s\n$`)
+		if !expected.MatchString(w.Body.String()) {
+			t.Errorf("Response body should match `%s`, was `%s`", expected, w.Body.String())
+		}
+	}
+}
+
+func TestHttpNagiosAggregate(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/status/v2/aggregate?format=nagios", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assertStatusCode(http.StatusOK, t, w)
+	assertContentTypeHeader("text/plain; charset=utf-8", t, w)
+	expected := regexp.MustCompile(`^SERVICE OK - All checks are OK \| 'aaa'=`)
+	if !expected.MatchString(w.Body.String()) {
+		t.Errorf("Response body should match `%s`, was `%s`", expected, w.Body.String())
+	}
+}
@@ -0,0 +1,158 @@
+package healthchecks
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPassiveTTL is the time a pushed status stays fresh when neither the push nor the PassiveCheck set a TTL
+const DefaultPassiveTTL = 5 * time.Minute
+
+// Maximum size of a pushed status body
+const maxPassivePushBytes = 64 * 1024
+
+// PassiveCheck is a StatusCheck whose status is pushed rather than polled, for signals coming from outside the
+// process such as a sidecar, a batch job or a deploy pipeline.
+//
+// The status is set from Go with Set, or with an authenticated `POST {prefix}/v2/{slug}` whose JSON body is a Status
+// with an optional `ttl` in seconds, e.g. `{"result":"OK","details":"Backup completed","ttl":3600}`. The request must
+// carry an `Authorization: Bearer {Token}` header. A status older than its TTL is stale and reported as CRITICAL, as is
+// a PassiveCheck that never received a status.
+type PassiveCheck struct {
+	Token string           // Optional bearer token of the push requests, pushing over HTTP is disabled when empty
+	TTL   time.Duration    // Optional TTL of the statuses set without one, defaults to DefaultPassiveTTL
+	Now   func() time.Time // Optional clock, defaults to time.Now
+
+	mu       sync.RWMutex
+	status   *Status
+	received time.Time
+	expires  time.Time
+}
+
+// The body of a push request
+type passivePush struct {
+	Status
+	TTL int64 `json:"ttl"`
+}
+
+// Set sets the status of the check, fresh for ttl or for the TTL of the check if ttl is 0. A status with a result
+// other than OK, WARN or CRIT is stored as CRIT.
+func (p *PassiveCheck) Set(status Status, ttl time.Duration) {
+	if !validResult(status.Result) {
+		status.Details = strings.TrimSuffix(fmt.Sprintf("Invalid result `%s`; %s", status.Result, status.Details), "; ")
+		status.Result = CRITICAL
+	}
+
+	if ttl <= 0 {
+		ttl = p.TTL
+	}
+	if ttl <= 0 {
+		ttl = DefaultPassiveTTL
+	}
+
+	now := p.now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = &status
+	p.received = now
+	p.expires = now.Add(ttl)
+}
+
+func (p *PassiveCheck) CheckStatus(name string) StatusList {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.status == nil {
+		return StatusList{
+			StatusList: []Status{
+				{
+					Description: name,
+					Result:      CRITICAL,
+					Details:     "No status received",
+				},
+			},
+		}
+	}
+
+	s := *p.status
+	if s.Description == "" {
+		s.Description = name
+	}
+
+	if now := p.now(); !now.Before(p.expires) {
+		s.Result = CRITICAL
+		s.Details = fmt.Sprintf(
+			"Status is stale, received at %s and expired at %s; %s",
+			p.received.Format(time.RFC3339),
+			p.expires.Format(time.RFC3339),
+			p.status.Details,
+		)
+	}
+
+	return StatusList{StatusList: []Status{s}}
+}
+
+func (p *PassiveCheck) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+// Check the bearer token of a push request in constant time
+func (p *PassiveCheck) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if p.Token == "" || token == r.Header.Get("Authorization") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(p.Token)) == 1
+}
+
+func validResult(result AlertLevel) bool {
+	return result == OK || result == WARNING || result == CRITICAL
+}
+
+// Get the PassiveCheck of a StatusEndpoint, nil if the endpoint is not passive
+func passiveCheck(statusEndpoint *StatusEndpoint) *PassiveCheck {
+	if statusEndpoint == nil {
+		return nil
+	}
+	p, _ := statusEndpoint.StatusCheck.(*PassiveCheck)
+	return p
+}
+
+// Respond to a push request to a passive endpoint with the resulting status
+func servePassivePush(w http.ResponseWriter, r *http.Request, statusEndpoint *StatusEndpoint, p *PassiveCheck) {
+	if !p.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized", fmt.Sprintf("Invalid bearer token for status endpoint: %s", statusEndpoint.Slug), APIV2)
+		return
+	}
+
+	push := passivePush{}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxPassivePushBytes)).Decode(&push); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid status", fmt.Sprintf("Error decoding json status: %s", err.Error()), APIV2)
+		return
+	}
+
+	if !validResult(push.Result) {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid status", fmt.Sprintf("Result must be one of OK, WARN or CRIT, was `%s`", push.Result), APIV2)
+		return
+	}
+	if push.TTL < 0 {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid status", fmt.Sprintf("TTL must be positive, was %d", push.TTL), APIV2)
+		return
+	}
+
+	p.Set(push.Status, time.Duration(push.TTL)*time.Second)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	io.WriteString(w, ExecuteStatusCheck(statusEndpoint, APIV2))
+}
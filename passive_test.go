@@ -0,0 +1,217 @@
+package healthchecks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPassiveCheck(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := &PassiveCheck{TTL: time.Minute, Now: func() time.Time { return now }}
+
+	s := p.CheckStatus("The backup")
+	if s.StatusList[0].Result != CRITICAL || s.StatusList[0].Details != "No status received" {
+		t.Errorf("Status should be `CRIT` `No status received`, was `%v`", s.StatusList[0])
+	}
+
+	p.Set(Status{Result: WARNING, Details: "Backup slow"}, 0)
+	now = now.Add(59 * time.Second)
+
+	actual := p.CheckStatus("The backup").StatusList[0]
+	expected := Status{Description: "The backup", Result: WARNING, Details: "Backup slow"}
+	if actual != expected {
+		t.Errorf("Status should be `%v`, was `%v`", expected, actual)
+	}
+
+	now = now.Add(time.Second)
+	actual = p.CheckStatus("The backup").StatusList[0]
+	expected = Status{
+		Description: "The backup",
+		Result:      CRITICAL,
+		Details:     "Status is stale, received at 2020-01-01T12:00:00Z and expired at 2020-01-01T12:01:00Z; Backup slow",
+	}
+	if actual != expected {
+		t.Errorf("Status should be `%v`, was `%v`", expected, actual)
+	}
+
+	// A TTL set with the status overrides the TTL of the check
+	p.Set(Status{Description: "Nightly backup", Result: OK, Details: "Backup completed"}, time.Hour)
+	now = now.Add(30 * time.Minute)
+	actual = p.CheckStatus("The backup").StatusList[0]
+	expected = Status{Description: "Nightly backup", Result: OK, Details: "Backup completed"}
+	if actual != expected {
+		t.Errorf("Status should be `%v`, was `%v`", expected, actual)
+	}
+}
+
+func TestPassiveCheckDefaultTTL(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := &PassiveCheck{Now: func() time.Time { return now }}
+
+	p.Set(Status{Result: OK}, 0)
+	now = now.Add(DefaultPassiveTTL - time.Second)
+	if actual := p.CheckStatus("The backup").StatusList[0]; actual.Result != OK {
+		t.Errorf("Result should be `OK`, was `%s`", actual.Result)
+	}
+
+	now = now.Add(time.Second)
+	if actual := p.CheckStatus("The backup").StatusList[0]; actual.Result != CRITICAL {
+		t.Errorf("Result should be `CRIT`, was `%s`", actual.Result)
+	}
+}
+
+func TestPassiveCheckInvalidResult(t *testing.T) {
+	p := &PassiveCheck{}
+
+	p.Set(Status{}, 0)
+	actual := p.CheckStatus("The backup").StatusList[0]
+	expected := Status{Description: "The backup", Result: CRITICAL, Details: "Invalid result ``"}
+	if actual != expected {
+		t.Errorf("Status should be `%v`, was `%v`", expected, actual)
+	}
+
+	p.Set(Status{Result: "MAYBE", Details: "Backup completed"}, 0)
+	actual = p.CheckStatus("The backup").StatusList[0]
+	expected = Status{Description: "The backup", Result: CRITICAL, Details: "Invalid result `MAYBE`; Backup completed"}
+	if actual != expected {
+		t.Errorf("Status should be `%v`, was `%v`", expected, actual)
+	}
+
+	// The status can be aggregated
+	aggregate := AggregateStatusList([]StatusEndpoint{{Name: "Backup", Slug: "backup", Type: "internal", StatusCheck: p}}, "")
+	if aggregate.StatusList[0].Result != CRITICAL {
+		t.Errorf("Aggregate result should be `CRIT`, was `%s`", aggregate.StatusList[0].Result)
+	}
+}
+
+func TestHttpPassivePush(t *testing.T) {
+	p := &PassiveCheck{Token: "secret"}
+	passiveHandler := passiveTestHandler(p)
+
+	w := push(passiveHandler, "/status/v2/backup", "secret", `{"result":"WARN","details":"Backup slow","ttl":3600}`)
+	assertSuccessfulJSONResponse(t, w)
+	assertBody(`{"description":"Backup","result":"WARN","details":"Backup slow"}`, t, w)
+
+	req, _ := http.NewRequest("GET", "/status/v2/backup", nil)
+	w = httptest.NewRecorder()
+	passiveHandler.ServeHTTP(w, req)
+	assertBody(`{"description":"Backup","result":"WARN","details":"Backup slow"}`, t, w)
+
+	// The passive check is part of the aggregate like any other check
+	req, _ = http.NewRequest("GET", "/status/v2/aggregate", nil)
+	w = httptest.NewRecorder()
+	passiveHandler.ServeHTTP(w, req)
+	if !strings.Contains(w.Body.String(), `"result":"WARN"`) {
+		t.Errorf("Aggregate should be `WARN`, was `%s`", w.Body.String())
+	}
+}
+
+func TestHttpPassivePushUnauthorized(t *testing.T) {
+	p := &PassiveCheck{Token: "secret"}
+	passiveHandler := passiveTestHandler(p)
+
+	for _, token := range []string{"", "wrong", "secrets"} {
+		w := push(passiveHandler, "/status/v2/backup", token, `{"result":"OK"}`)
+
+		assertStatusCode(http.StatusUnauthorized, t, w)
+		if w.Header().Get("WWW-Authenticate") != "Bearer" {
+			t.Errorf("WWW-Authenticate header should be `Bearer`, was: %s", w.Header().Get("WWW-Authenticate"))
+		}
+	}
+
+	if actual := p.CheckStatus("Backup").StatusList[0]; actual.Details != "No status received" {
+		t.Errorf("Status should not be set, was `%v`", actual)
+	}
+
+	// Pushing over HTTP is disabled without a token
+	w := push(passiveTestHandler(&PassiveCheck{}), "/status/v2/backup", "", `{"result":"OK"}`)
+	assertStatusCode(http.StatusUnauthorized, t, w)
+}
+
+func TestHttpPassivePushInvalid(t *testing.T) {
+	passiveHandler := passiveTestHandler(&PassiveCheck{Token: "secret"})
+
+	expected := []struct {
+		body    string
+		details string
+	}{
+		{`{"result":`, "Error decoding json status: unexpected EOF"},
+		{`{"result":"MAYBE"}`, "Result must be one of OK, WARN or CRIT, was `MAYBE`"},
+		{`{"result":"OK","ttl":-1}`, "TTL must be positive, was -1"},
+	}
+	for _, e := range expected {
+		w := push(passiveHandler, "/status/v2/backup", "secret", e.body)
+
+		assertStatusCode(http.StatusBadRequest, t, w)
+		assertBody(`{"description":"Invalid status","result":"CRIT","details":"`+e.details+`"}`, t, w)
+	}
+}
+
+func TestHttpPassivePushNotAllowed(t *testing.T) {
+	passiveHandler := passiveTestHandler(&PassiveCheck{Token: "secret"})
+
+	// Only the V2 endpoint of a passive check accepts pushes
+	for _, path := range []string{"/status/backup", "/status/v2/aaa", "/status/v2/about"} {
+		w := push(passiveHandler, path, "secret", `{"result":"OK"}`)
+
+		assertStatusCode(http.StatusMethodNotAllowed, t, w)
+		if w.Header().Get("Allow") != "GET, HEAD" {
+			t.Errorf("Allow header should be `GET, HEAD`, was: %s", w.Header().Get("Allow"))
+		}
+	}
+
+	// POST is allowed on the V2 endpoint of a passive check
+	req, _ := http.NewRequest("PUT", "/status/v2/backup", nil)
+	w := httptest.NewRecorder()
+	passiveHandler.ServeHTTP(w, req)
+	assertStatusCode(http.StatusMethodNotAllowed, t, w)
+	if w.Header().Get("Allow") != "GET, HEAD, POST" {
+		t.Errorf("Allow header should be `GET, HEAD, POST`, was: %s", w.Header().Get("Allow"))
+	}
+
+	req, _ = http.NewRequest("PUT", "/status/backup", nil)
+	w = httptest.NewRecorder()
+	passiveHandler.ServeHTTP(w, req)
+	if w.Header().Get("Allow") != "GET, HEAD" {
+		t.Errorf("Allow header should be `GET, HEAD`, was: %s", w.Header().Get("Allow"))
+	}
+}
+
+/* HELPER FUNCTIONS */
+func passiveTestHandler(p *PassiveCheck) http.Handler {
+	return Handler([]StatusEndpoint{
+		{
+			Name:          "AAA",
+			Slug:          "aaa",
+			Type:          "internal",
+			IsTraversable: false,
+			StatusCheck:   MockStatusChecker{"AAA", OK, "all good"},
+			TraverseCheck: nil,
+		},
+		{
+			Name:          "Backup",
+			Slug:          "backup",
+			Type:          "internal",
+			IsTraversable: false,
+			StatusCheck:   p,
+			TraverseCheck: nil,
+		},
+	},
+		"test/about.json",
+		"test/version.txt",
+		make(map[string]interface{}),
+	)
+}
+
+func push(h http.Handler, path string, token string, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}